@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/rand"
+	"fmt"
 	"log"
 	"os"
 
@@ -10,20 +12,55 @@ import (
 	"github.com/arllanos/minesweeper-API/internal/services"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort       = "8080"
+	defaultSQLitePath = "minesweeper.db"
+)
 
 func main() {
 	// initialize dependencies
-	gameRepository := repository.NewRedisRepository()
-	gameService := services.NewGameService(gameRepository)
+	repositories, err := newRepositories(os.Getenv("STORAGE"))
+	if err != nil {
+		log.Fatalf("Failed to initialize storage %v", err)
+	}
+	gameRepository := repositories.Games
+	leaderboardRepository := repositories.Leaderboards
+	dailyKey := newDailyKey(os.Getenv("DAILY_SECRET"))
+	gameService := services.NewGameService(gameRepository, leaderboardRepository, dailyKey)
 	gameHandler := handler.NewGameHandler(gameService)
+	dailyRepository := repositories.Daily
+	dailyService := services.NewDailyService(gameService, gameRepository, dailyRepository, dailyKey)
+	dailyHandler := handler.NewDailyHandler(dailyService)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepository)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardService)
 	httpRouter := router.NewChiRouter()
 
 	// register routes
 	httpRouter.POST("/users", gameHandler.CreateUser)
+	httpRouter.GET("/users/{username}/stats", gameHandler.GetUserStats)
+	httpRouter.GET("/users/{username}/games", gameHandler.GetUserGames)
 	httpRouter.PUT("/games", gameHandler.CreateGame)
+	httpRouter.GET("/games/{gamename}/{username}", gameHandler.GetGame)
+	httpRouter.DELETE("/games/{gamename}/{username}", gameHandler.DeleteGame)
 	httpRouter.POST("/games/{gamename}/{username}/click", gameHandler.ClickCell)
+	httpRouter.POST("/games/{gamename}/{username}/undo", gameHandler.Undo)
+	httpRouter.POST("/games/{gamename}/{username}/resign", gameHandler.Resign)
+	httpRouter.GET("/games/{gamename}/{username}/moves", gameHandler.GetMoves)
+	httpRouter.GET("/games/{gamename}/{username}/replay", gameHandler.GetReplay)
+	httpRouter.GET("/games/{gamename}/{username}/verify", gameHandler.GetVerification)
 	httpRouter.GET("/games/{gamename}/{username}/board", gameHandler.GetBoard)
+	httpRouter.GET("/games/{gamename}/{username}/hint", gameHandler.GetHint)
+	httpRouter.GET("/games/{gamename}/{username}/heatmap", gameHandler.GetHeatmap)
+	httpRouter.GET("/leaderboards/{difficulty}", leaderboardHandler.GetLeaderboard)
+	httpRouter.GET("/daily", dailyHandler.GetChallenge)
+	httpRouter.GET("/daily/leaderboard", dailyHandler.GetLeaderboard)
+	httpRouter.POST("/daily/{username}/click", dailyHandler.ClickCell)
+	httpRouter.GET("/daily/{username}/board", dailyHandler.GetBoard)
+
+	// the admin API discloses mine positions, keep it off unless explicitly enabled
+	if os.Getenv("ENABLE_ADMIN_API") == "true" {
+		httpRouter.GET("/admin/games/{gamename}/board", gameHandler.GetDebugBoard)
+	}
 
 	// start the server
 	port := os.Getenv("PORT")
@@ -34,3 +71,44 @@ func main() {
 		log.Fatalf("Failed to start server %v", err)
 	}
 }
+
+// newRepositories returns the storage backend selected by name, Redis by
+// default.
+func newRepositories(storage string) (*repository.Repositories, error) {
+	switch storage {
+	case "", "redis":
+		// move data stored with older key layouts before serving
+		if err := repository.MigrateRedisKeyspace(); err != nil {
+			return nil, err
+		}
+		return repository.NewRedisRepositories(), nil
+	case "postgres":
+		return repository.NewPostgresRepositories(os.Getenv("POSTGRES_DSN"))
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		return repository.NewSQLiteRepositories(path)
+	case "memory":
+		log.Printf("Using in-memory storage, data is lost on restart")
+		return repository.NewMemoryRepositories(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", storage)
+	}
+}
+
+// newDailyKey returns the key daily boards are derived from. Without a
+// configured secret a random one is used, daily boards then change on restart.
+func newDailyKey(secret string) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	log.Printf("Warning: DAILY_SECRET not set, daily boards will change on restart")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Failed to generate daily key %v", err)
+	}
+	return key
+}
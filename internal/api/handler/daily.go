@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+type dailyHandler struct {
+	dailyService services.DailyService
+}
+
+type DailyHandler interface {
+	GetChallenge(response http.ResponseWriter, request *http.Request)
+	ClickCell(response http.ResponseWriter, request *http.Request)
+	GetBoard(response http.ResponseWriter, request *http.Request)
+	GetLeaderboard(response http.ResponseWriter, request *http.Request)
+}
+
+func NewDailyHandler(service services.DailyService) DailyHandler {
+	return &dailyHandler{
+		dailyService: service,
+	}
+}
+
+func (h *dailyHandler) GetChallenge(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	challenge, err := h.dailyService.Today()
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(challenge)
+}
+
+func (h *dailyHandler) ClickCell(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+	var click domain.ClickData
+	err := json.NewDecoder(request.Body).Decode(&click)
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: "Error unmarshalling data"})
+		return
+	}
+
+	// extract path variables from request context (router-specific logic handled externally)
+	userName := request.Context().Value("userName").(string)
+
+	result, err1 := h.dailyService.Click(userName, &click)
+	if err1 != nil {
+		if err1.Error() == "game_conflict" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
+		if err1.Error() == "bad_click_kind" || err1.Error() == "game_over" || err1.Error() == "game_won" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(result)
+}
+
+func (h *dailyHandler) GetBoard(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	userName := request.Context().Value("userName").(string)
+
+	board, err := h.dailyService.Board(userName)
+	if err != nil {
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username not exists or daily challenge not started"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	_, _ = response.Write([]byte(board))
+}
+
+func (h *dailyHandler) GetLeaderboard(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	ranking, err := h.dailyService.Leaderboard(request.URL.Query().Get("date"))
+	if err != nil {
+		if err.Error() == "bad_date" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(ranking)
+}
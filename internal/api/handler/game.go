@@ -3,6 +3,8 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/arllanos/minesweeper-API/internal/domain"
 	"github.com/arllanos/minesweeper-API/internal/errors"
@@ -15,9 +17,21 @@ type handler struct {
 
 type GameHandler interface {
 	CreateUser(response http.ResponseWriter, request *http.Request)
+	GetUserStats(response http.ResponseWriter, request *http.Request)
+	GetUserGames(response http.ResponseWriter, request *http.Request)
+	GetGame(response http.ResponseWriter, request *http.Request)
+	DeleteGame(response http.ResponseWriter, request *http.Request)
 	CreateGame(response http.ResponseWriter, request *http.Request)
 	ClickCell(response http.ResponseWriter, request *http.Request)
+	Undo(response http.ResponseWriter, request *http.Request)
+	Resign(response http.ResponseWriter, request *http.Request)
+	GetMoves(response http.ResponseWriter, request *http.Request)
+	GetReplay(response http.ResponseWriter, request *http.Request)
+	GetVerification(response http.ResponseWriter, request *http.Request)
 	GetBoard(response http.ResponseWriter, request *http.Request)
+	GetDebugBoard(response http.ResponseWriter, request *http.Request)
+	GetHint(response http.ResponseWriter, request *http.Request)
+	GetHeatmap(response http.ResponseWriter, request *http.Request)
 }
 
 func NewGameHandler(service services.GameService) GameHandler {
@@ -49,6 +63,11 @@ func (h *handler) CreateUser(response http.ResponseWriter, request *http.Request
 			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
 			return
 		}
+		if err1.Error() == "bad_username" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
 		response.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
 		return
@@ -57,6 +76,75 @@ func (h *handler) CreateUser(response http.ResponseWriter, request *http.Request
 	json.NewEncoder(response).Encode(result)
 }
 
+func (h *handler) GetUserStats(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	userName := request.Context().Value("userName").(string)
+
+	stats, err := h.gameService.Stats(userName)
+	if err != nil {
+		if err.Error() == "user_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username not exists"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(stats)
+}
+
+func (h *handler) GetUserGames(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	userName := request.Context().Value("userName").(string)
+
+	limit := 0
+	if value := request.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "bad_limit"})
+			return
+		}
+	}
+
+	// statuses can be repeated or comma separated: ?status=over,won
+	var statuses []string
+	for _, value := range request.URL.Query()["status"] {
+		for _, status := range strings.Split(value, ",") {
+			if status != "" {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	page, err := h.gameService.Games(userName, statuses, limit, request.URL.Query().Get("cursor"))
+	if err != nil {
+		if err.Error() == "user_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username not exists"})
+			return
+		}
+		if err.Error() == "bad_status" || err.Error() == "bad_limit" || err.Error() == "bad_cursor" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(page)
+}
+
 func (h *handler) CreateGame(response http.ResponseWriter, request *http.Request) {
 	response.Header().Set("Content-Type", "application/json")
 	var game domain.Game
@@ -69,11 +157,31 @@ func (h *handler) CreateGame(response http.ResponseWriter, request *http.Request
 
 	result, err1 := h.gameService.CreateGame(&game)
 	if err1 != nil {
+		if err1.Error() == "game_conflict" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
+		if _, ok := err1.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
 		if err1.Error() == "user_not_found" {
 			response.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username not exists"})
 			return
 		}
+		if verr, ok := err1.(*errors.ValidationError); ok {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: verr.Error(), Details: verr.Fields})
+			return
+		}
+		if err1.Error() == "bad_game_name" || err1.Error() == "reserved_game_name" || err1.Error() == "reserved_seed" || err1.Error() == "player_not_found" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
 		response.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
 		return
@@ -83,6 +191,65 @@ func (h *handler) CreateGame(response http.ResponseWriter, request *http.Request
 	json.NewEncoder(response).Encode(result)
 }
 
+func (h *handler) GetGame(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	game, err := h.gameService.Game(gameName, userName)
+	if err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(game)
+}
+
+func (h *handler) DeleteGame(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	if err := h.gameService.DeleteGame(gameName, userName); err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
 func (h *handler) ClickCell(response http.ResponseWriter, request *http.Request) {
 	response.Header().Set("Content-Type", "application/json")
 	var click domain.ClickData
@@ -99,7 +266,17 @@ func (h *handler) ClickCell(response http.ResponseWriter, request *http.Request)
 
 	result, err1 := h.gameService.Click(gameName, userName, &click)
 	if err1 != nil {
-		if err1.Error() == "bad_click_kind" || err1.Error() == "game_over" || err1.Error() == "game_won" {
+		if err1.Error() == "game_conflict" || err1.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
+		if _, ok := err1.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
+			return
+		}
+		if err1.Error() == "bad_click_kind" || err1.Error() == "game_over" || err1.Error() == "game_won" || err1.Error() == "no_guess_board_unavailable" {
 			response.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(response).Encode(errors.ServiceError{Message: err1.Error()})
 			return
@@ -112,6 +289,181 @@ func (h *handler) ClickCell(response http.ResponseWriter, request *http.Request)
 	json.NewEncoder(response).Encode(result)
 }
 
+func (h *handler) Undo(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	result, err := h.gameService.Undo(gameName, userName)
+	if err != nil {
+		if err.Error() == "game_conflict" || err.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "undo_disabled" || err.Error() == "undo_budget_exhausted" || err.Error() == "nothing_to_undo" || err.Error() == "game_won" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(result)
+}
+
+func (h *handler) Resign(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	result, err := h.gameService.Resign(gameName, userName)
+	if err != nil {
+		if err.Error() == "game_conflict" || err.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "game_not_lost" || err.Error() == "loss_already_final" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(result)
+}
+
+func (h *handler) GetMoves(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	moves, err := h.gameService.Moves(gameName, userName)
+	if err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(moves)
+}
+
+func (h *handler) GetReplay(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	n := -1
+	if value := request.URL.Query().Get("move"); value != "" {
+		var err error
+		if n, err = strconv.Atoi(value); err != nil || n < 0 {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "bad_move_number"})
+			return
+		}
+	}
+
+	result, err := h.gameService.Replay(gameName, userName, n)
+	if err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "bad_move_number" || err.Error() == "game_in_progress" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(result)
+}
+
+func (h *handler) GetVerification(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	verification, err := h.gameService.Verify(gameName, userName)
+	if err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(verification)
+}
+
 func (h *handler) GetBoard(response http.ResponseWriter, request *http.Request) {
 	response.Header().Set("Content-Type", "application/json")
 
@@ -121,6 +473,11 @@ func (h *handler) GetBoard(response http.ResponseWriter, request *http.Request)
 
 	board, err := h.gameService.Board(gameName, userName)
 	if err != nil {
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
 		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
 			response.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
@@ -134,3 +491,101 @@ func (h *handler) GetBoard(response http.ResponseWriter, request *http.Request)
 	response.WriteHeader(http.StatusOK)
 	_, _ = response.Write([]byte(board))
 }
+
+func (h *handler) GetHint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	hint, err := h.gameService.Hint(gameName, userName)
+	if err != nil {
+		if err.Error() == "game_conflict" || err.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "game_over" || err.Error() == "game_won" || err.Error() == "no_hint_available" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(hint)
+}
+
+func (h *handler) GetHeatmap(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+	userName := request.Context().Value("userName").(string)
+
+	heatmap, err := h.gameService.Heatmap(gameName, userName)
+	if err != nil {
+		if err.Error() == "game_conflict" || err.Error() == "daily_game" {
+			response.WriteHeader(http.StatusConflict)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if _, ok := err.(*errors.ForbiddenError); ok {
+			response.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "user_not_found" || err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Username or game not exists"})
+			return
+		}
+		if err.Error() == "game_over" || err.Error() == "game_won" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(heatmap)
+}
+
+func (h *handler) GetDebugBoard(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	gameName := request.Context().Value("gameName").(string)
+
+	board, err := h.gameService.DebugBoard(gameName)
+	if err != nil {
+		if err.Error() == "game_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "Game not exists"})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	_, _ = response.Write([]byte(board))
+}
@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/api/handler"
+	"github.com/arllanos/minesweeper-API/internal/api/router"
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/arllanos/minesweeper-API/internal/repository"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve runs a handler the way the routers do, with the path variables in the
+// request context.
+func serve(f func(http.ResponseWriter, *http.Request), method string, body string, params map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "/", strings.NewReader(body))
+	response := httptest.NewRecorder()
+	router.WrapHandler(f, func(*http.Request) map[string]string { return params })(response, request)
+	return response
+}
+
+func TestStrangersAreForbidden(t *testing.T) {
+	repos := repository.NewMemoryRepositories()
+	service := services.NewGameService(repos.Games, repos.Leaderboards, []byte("secret"))
+	for _, userName := range []string{"alice", "bob", "carol"} {
+		_, err := service.CreateUser(&domain.User{Username: userName})
+		assert.Nil(t, err)
+	}
+	_, err := service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Players: []string{"bob"}, Difficulty: "beginner", Seed: 42, UndoLimit: 3})
+	assert.Nil(t, err)
+	_, err = service.Click("game1", "alice", &domain.ClickData{Row: 4, Col: 4, Kind: "click"})
+	assert.Nil(t, err)
+
+	gameHandler := handler.NewGameHandler(service)
+
+	// a listed player can play but not delete the game
+	bob := map[string]string{"gameName": "game1", "userName": "bob"}
+	assert.Equal(t, http.StatusOK, serve(gameHandler.GetBoard, http.MethodGet, "", bob).Code)
+	assert.Equal(t, http.StatusForbidden, serve(gameHandler.DeleteGame, http.MethodDelete, "", bob).Code)
+
+	requests := []struct {
+		name    string
+		handle  func(http.ResponseWriter, *http.Request)
+		method  string
+		body    string
+		allowed int
+	}{
+		{"GetGame", gameHandler.GetGame, http.MethodGet, "", http.StatusOK},
+		{"ClickCell", gameHandler.ClickCell, http.MethodPost, `{"row":0,"col":0,"kind":"flag"}`, http.StatusOK},
+		{"Undo", gameHandler.Undo, http.MethodPost, "", http.StatusOK},
+		{"GetBoard", gameHandler.GetBoard, http.MethodGet, "", http.StatusOK},
+		{"GetHint", gameHandler.GetHint, http.MethodGet, "", http.StatusOK},
+		{"GetHeatmap", gameHandler.GetHeatmap, http.MethodGet, "", http.StatusOK},
+		{"DeleteGame", gameHandler.DeleteGame, http.MethodDelete, "", http.StatusNoContent},
+	}
+
+	for _, r := range requests {
+		t.Run(r.name, func(t *testing.T) {
+			response := serve(r.handle, r.method, r.body, map[string]string{"gameName": "game1", "userName": "carol"})
+			assert.Equal(t, http.StatusForbidden, response.Code)
+			var serviceError errors.ServiceError
+			assert.Nil(t, json.NewDecoder(response.Body).Decode(&serviceError))
+			assert.Equal(t, "forbidden", serviceError.Message)
+
+			response = serve(r.handle, r.method, r.body, map[string]string{"gameName": "game1", "userName": "alice"})
+			assert.Equal(t, r.allowed, response.Code)
+		})
+	}
+}
+
+// Get and delete are served at /games/{gamename}/{username} rather than at
+// /games/{gamename}: with no authentication the caller is named in the path,
+// like on every other game route.
+func TestGetAndDeleteNameTheCallerInThePath(t *testing.T) {
+	repos := repository.NewMemoryRepositories()
+	service := services.NewGameService(repos.Games, repos.Leaderboards, []byte("secret"))
+	for _, userName := range []string{"alice", "bob"} {
+		_, err := service.CreateUser(&domain.User{Username: userName})
+		assert.Nil(t, err)
+	}
+	_, err := service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Difficulty: "beginner"})
+	assert.Nil(t, err)
+
+	gameHandler := handler.NewGameHandler(service)
+	alice := map[string]string{"gameName": "game1", "userName": "alice"}
+
+	response := serve(gameHandler.GetGame, http.MethodGet, "", alice)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var game domain.Game
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&game))
+	assert.Equal(t, "alice", game.Username)
+
+	assert.Equal(t, http.StatusForbidden, serve(gameHandler.GetGame, http.MethodGet, "", map[string]string{"gameName": "game1", "userName": "bob"}).Code)
+	assert.Equal(t, http.StatusNotFound, serve(gameHandler.GetGame, http.MethodGet, "", map[string]string{"gameName": "game1", "userName": "nobody"}).Code)
+
+	assert.Equal(t, http.StatusNoContent, serve(gameHandler.DeleteGame, http.MethodDelete, "", alice).Code)
+	assert.Equal(t, http.StatusNotFound, serve(gameHandler.GetGame, http.MethodGet, "", alice).Code)
+}
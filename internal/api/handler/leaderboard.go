@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+type leaderboardHandler struct {
+	leaderboardService services.LeaderboardService
+}
+
+type LeaderboardHandler interface {
+	GetLeaderboard(response http.ResponseWriter, request *http.Request)
+}
+
+func NewLeaderboardHandler(service services.LeaderboardService) LeaderboardHandler {
+	return &leaderboardHandler{
+		leaderboardService: service,
+	}
+}
+
+func (h *leaderboardHandler) GetLeaderboard(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+
+	// extract path variables from request context (router-specific logic handled externally)
+	difficulty := request.Context().Value("difficulty").(string)
+
+	limit := 0
+	if value := request.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: "bad_limit"})
+			return
+		}
+	}
+
+	page, err := h.leaderboardService.Leaderboard(difficulty, limit, request.URL.Query().Get("cursor"))
+	if err != nil {
+		if err.Error() == "leaderboard_not_found" {
+			response.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		if err.Error() == "bad_limit" || err.Error() == "bad_cursor" {
+			response.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		response.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(response).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+	json.NewEncoder(response).Encode(page)
+}
@@ -27,6 +27,10 @@ func (r *chiRouter) PUT(uri string, f func(w http.ResponseWriter, r *http.Reques
 	r.dispatcher.Put(uri, WrapHandler(f, chiExtractParams))
 }
 
+func (r *chiRouter) DELETE(uri string, f func(w http.ResponseWriter, r *http.Request)) {
+	r.dispatcher.Delete(uri, WrapHandler(f, chiExtractParams))
+}
+
 func (r *chiRouter) SERVE(port string) error {
 	log.Printf("Chi HTTP server running on port %v", port)
 	return http.ListenAndServe(":"+port, r.dispatcher)
@@ -34,7 +38,8 @@ func (r *chiRouter) SERVE(port string) error {
 
 func chiExtractParams(r *http.Request) map[string]string {
 	return map[string]string{
-		"gameName": chi.URLParam(r, "gamename"),
-		"userName": chi.URLParam(r, "username"),
+		"gameName":   chi.URLParam(r, "gamename"),
+		"userName":   chi.URLParam(r, "username"),
+		"difficulty": chi.URLParam(r, "difficulty"),
 	}
 }
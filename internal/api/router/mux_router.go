@@ -27,6 +27,10 @@ func (r *muxRouter) PUT(uri string, f func(w http.ResponseWriter, r *http.Reques
 	r.dispatcher.HandleFunc(uri, WrapHandler(f, muxExtractParams)).Methods(http.MethodPut)
 }
 
+func (r *muxRouter) DELETE(uri string, f func(w http.ResponseWriter, r *http.Request)) {
+	r.dispatcher.HandleFunc(uri, WrapHandler(f, muxExtractParams)).Methods(http.MethodDelete)
+}
+
 func (r *muxRouter) SERVE(port string) error {
 	log.Printf("Mux HTTP server running on port %v", port)
 	return http.ListenAndServe(":"+port, r.dispatcher)
@@ -35,7 +39,8 @@ func (r *muxRouter) SERVE(port string) error {
 func muxExtractParams(r *http.Request) map[string]string {
 	vars := mux.Vars(r)
 	return map[string]string{
-		"gameName": vars["gamename"],
-		"userName": vars["username"],
+		"gameName":   vars["gamename"],
+		"userName":   vars["username"],
+		"difficulty": vars["difficulty"],
 	}
 }
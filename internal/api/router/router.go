@@ -9,6 +9,7 @@ type Router interface {
 	GET(uri string, f func(w http.ResponseWriter, r *http.Request))
 	POST(uri string, f func(w http.ResponseWriter, r *http.Request))
 	PUT(uri string, f func(w http.ResponseWriter, r *http.Request))
+	DELETE(uri string, f func(w http.ResponseWriter, r *http.Request))
 	SERVE(port string) error
 }
 
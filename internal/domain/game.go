@@ -3,22 +3,122 @@ package domain
 import "time"
 
 type Game struct {
-	Name      string        `json:"name"`
-	Username  string        `json:"username"`
-	Rows      int           `json:"rows"`
-	Cols      int           `json:"cols"`
-	Mines     int           `json:"mines"`
-	Status    string        `json:"status"`
-	Board     [][]byte      `json:"board"`
-	Clicks    int           `json:"clicks"`
-	CreatedAt time.Time     `json:"created_at,omitempty"`
-	StartedAt time.Time     `json:"started_at"`
-	TimeSpent time.Duration `json:"time_spent"`
+	Name             string        `json:"name"`
+	Username         string        `json:"username"`
+	Players          []string      `json:"players,omitempty"`
+	Rows             int           `json:"rows"`
+	Cols             int           `json:"cols"`
+	Mines            int           `json:"mines"`
+	Difficulty       string        `json:"difficulty,omitempty"`
+	FirstClick       string        `json:"first_click,omitempty"`
+	NoGuess          bool          `json:"no_guess,omitempty"`
+	Seed             int64         `json:"seed,omitempty"`
+	CustomSeed       bool          `json:"custom_seed,omitempty"`
+	Mode             string        `json:"mode,omitempty"`
+	Status           string        `json:"status"`
+	Board            [][]byte      `json:"board,omitempty"`
+	Clicks           int           `json:"clicks"`
+	Hints            int           `json:"hints"`
+	UndoLimit        int           `json:"undo_limit"`
+	Undos            int           `json:"undos"`
+	CreatedAt        time.Time     `json:"created_at,omitempty"`
+	StartedAt        time.Time     `json:"started_at"`
+	TimeSpent        time.Duration `json:"time_spent"`
+	ThreeBV          int           `json:"3bv"`
+	ThreeBVPerSecond float64       `json:"3bv_per_second,omitempty"`
+	Efficiency       float64       `json:"efficiency,omitempty"`
+	Recorded         bool          `json:"recorded,omitempty"`
+	Version          int64         `json:"version"`
+}
+
+type Move struct {
+	Row    int       `json:"row"`
+	Col    int       `json:"col"`
+	Kind   string    `json:"kind"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+type Verification struct {
+	Game       string   `json:"game"`
+	Moves      int      `json:"moves"`
+	Valid      bool     `json:"valid"`
+	Suspicious bool     `json:"suspicious"`
+	Passed     bool     `json:"passed"`
+	Problems   []string `json:"problems,omitempty"`
+}
+
+type Snapshot struct {
+	Board     [][]byte  `json:"board"`
+	Clicks    int       `json:"clicks"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
 }
 
 type User struct {
 	Username  string    `json:"username"`
 	CreatedAt time.Time `json:"createdAt"`
+	Stats     UserStats `json:"stats"`
+	Version   int64     `json:"version"`
+}
+
+type UserStats struct {
+	GamesPlayed       int                      `json:"games_played"`
+	Wins              int                      `json:"wins"`
+	Losses            int                      `json:"losses"`
+	WinRate           float64                  `json:"win_rate"`
+	CurrentStreak     int                      `json:"current_streak"`
+	LongestStreak     int                      `json:"longest_streak"`
+	BestTimes         map[string]time.Duration `json:"best_times"`
+	AverageEfficiency float64                  `json:"average_efficiency"`
+}
+
+type DailyChallenge struct {
+	Date     string `json:"date"`
+	Rows     int    `json:"rows"`
+	Cols     int    `json:"cols"`
+	Mines    int    `json:"mines"`
+	StartRow int    `json:"start_row"`
+	StartCol int    `json:"start_col"`
+}
+
+type DailyAttempt struct {
+	Date       string        `json:"date"`
+	Username   string        `json:"username"`
+	Game       string        `json:"game"`
+	Status     string        `json:"status"`
+	Clicks     int           `json:"clicks"`
+	Hints      int           `json:"hints"`
+	Verified   bool          `json:"verified"`
+	TimeSpent  time.Duration `json:"time_spent"`
+	FinishedAt time.Time     `json:"finished_at,omitempty"`
+}
+
+type GamePage struct {
+	Games      []*Game `json:"games"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type LeaderboardEntry struct {
+	Rank             int           `json:"rank"`
+	Username         string        `json:"username"`
+	Game             string        `json:"game"`
+	Difficulty       string        `json:"difficulty"`
+	Rows             int           `json:"rows"`
+	Cols             int           `json:"cols"`
+	Mines            int           `json:"mines"`
+	Clicks           int           `json:"clicks"`
+	TimeSpent        time.Duration `json:"time_spent"`
+	ThreeBV          int           `json:"3bv"`
+	ThreeBVPerSecond float64       `json:"3bv_per_second"`
+	Efficiency       float64       `json:"efficiency"`
+	FinishedAt       time.Time     `json:"finished_at"`
+}
+
+type LeaderboardPage struct {
+	Leaderboard string              `json:"leaderboard"`
+	Entries     []*LeaderboardEntry `json:"entries"`
+	NextCursor  string              `json:"next_cursor,omitempty"`
 }
 
 type ClickData struct {
@@ -26,3 +126,26 @@ type ClickData struct {
 	Col  int    `json:"col"`
 	Kind string `json:"kind"`
 }
+
+type Hint struct {
+	Row         int          `json:"row"`
+	Col         int          `json:"col"`
+	Mine        bool         `json:"mine"`
+	Probability float64      `json:"probability"`
+	Rule        string       `json:"rule"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+}
+
+type Constraint struct {
+	Row   int      `json:"row"`
+	Col   int      `json:"col"`
+	Cells [][2]int `json:"cells"`
+	Mines int      `json:"mines"`
+}
+
+type Heatmap struct {
+	Rows          int         `json:"rows"`
+	Cols          int         `json:"cols"`
+	Exact         bool        `json:"exact"`
+	Probabilities [][]float64 `json:"probabilities"`
+}
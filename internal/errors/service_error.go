@@ -1,5 +1,36 @@
 package errors
 
 type ServiceError struct {
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
+
+// ValidationError reports every field of a request that failed validation.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid_game_config"
+}
+
+// Add records a validation failure for the given field.
+func (e *ValidationError) Add(field string, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// ForbiddenError reports a user acting on a game that is neither theirs nor
+// shared with them.
+type ForbiddenError struct {
+	Game     string
+	Username string
+}
+
+func (e *ForbiddenError) Error() string {
+	return "forbidden"
+}
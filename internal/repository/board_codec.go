@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"log"
+)
+
+// Boards are stored in a compact binary form: a version byte, the number of
+// rows and columns as uvarints, then every cell in 4 bits, row by row, two
+// cells per byte with the first one in the high nibble.
+//
+// Boards used to be stored as JSON, an array of base64 rows. Those records
+// start with '[' (or are "null"), which is never a version byte, and are still
+// read. They are rewritten in the binary form the next time the game is saved.
+const boardCodecV1 = 1
+
+// maxBoardSide bounds the size read from a record, way above any real board.
+const maxBoardSide = 1 << 15
+
+// boardCells lists the cells a board is made of, a cell is stored as its index.
+var boardCells = []byte("EMemXB12345678")
+
+var boardCellCodes = func() [256]byte {
+	var codes [256]byte
+	for i := range codes {
+		codes[i] = 0xff
+	}
+	for code, cell := range boardCells {
+		codes[cell] = byte(code)
+	}
+	return codes
+}()
+
+func encodeBoard(board [][]byte) ([]byte, error) {
+	rows, cols := len(board), 0
+	if rows > 0 {
+		cols = len(board[0])
+	}
+
+	data := make([]byte, 0, 1+2*binary.MaxVarintLen64+(rows*cols+1)/2)
+	data = append(data, boardCodecV1)
+	data = binary.AppendUvarint(data, uint64(rows))
+	data = binary.AppendUvarint(data, uint64(cols))
+
+	n := 0
+	for _, row := range board {
+		if len(row) != cols {
+			log.Printf("Error: Unable to encode board data: rows of different lengths")
+			return nil, ErrMarshalData
+		}
+		for _, cell := range row {
+			code := boardCellCodes[cell]
+			if code == 0xff {
+				log.Printf("Error: Unable to encode board data: unknown cell %q", cell)
+				return nil, ErrMarshalData
+			}
+			if n%2 == 0 {
+				data = append(data, code<<4)
+			} else {
+				data[len(data)-1] |= code
+			}
+			n++
+		}
+	}
+
+	return data, nil
+}
+
+func decodeBoard(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrUnmarshalData
+	}
+	if data[0] == '[' || data[0] == 'n' {
+		return decodeJSONBoard(data)
+	}
+	if data[0] != boardCodecV1 {
+		log.Printf("Error: Unable to decode board data: unknown version %d", data[0])
+		return nil, ErrUnmarshalData
+	}
+
+	data = data[1:]
+	rows, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrUnmarshalData
+	}
+	data = data[n:]
+	cols, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrUnmarshalData
+	}
+	data = data[n:]
+
+	if rows == 0 {
+		return nil, nil
+	}
+	if rows > maxBoardSide || cols > maxBoardSide || uint64(len(data)) != (rows*cols+1)/2 {
+		return nil, ErrUnmarshalData
+	}
+
+	board := make([][]byte, rows)
+	i := 0
+	for r := range board {
+		board[r] = make([]byte, cols)
+		for c := range board[r] {
+			code := data[i/2] >> 4
+			if i%2 == 1 {
+				code = data[i/2] & 0x0f
+			}
+			if int(code) >= len(boardCells) {
+				return nil, ErrUnmarshalData
+			}
+			board[r][c] = boardCells[code]
+			i++
+		}
+	}
+
+	return board, nil
+}
+
+func decodeJSONBoard(data []byte) ([][]byte, error) {
+	var board [][]byte
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, ErrUnmarshalData
+	}
+	return board, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardCodec(t *testing.T) {
+	boards := [][][]byte{
+		nil,
+		{[]byte("E")},
+		{[]byte("EMemX"), []byte("B1234"), []byte("5678E")},
+		{[]byte("EM"), []byte("ee")},
+	}
+	for _, board := range boards {
+		data, err := encodeBoard(board)
+		assert.Nil(t, err)
+		decoded, err := decodeBoard(data)
+		assert.Nil(t, err)
+		assert.Equal(t, board, decoded)
+	}
+}
+
+func TestEncodeBoardIsCompact(t *testing.T) {
+	board := make([][]byte, 16)
+	for i := range board {
+		board[i] = []byte("EEEEEEEEEEEEEEMMMMMMMMMMMMMMMM")
+	}
+
+	data, err := encodeBoard(board)
+	assert.Nil(t, err)
+	// version, rows, cols and half a byte per cell
+	assert.Equal(t, 3+16*30/2, len(data))
+
+	legacy, _ := json.Marshal(board)
+	assert.Less(t, len(data)*2, len(legacy))
+}
+
+func TestEncodeBoardRejectsUnknownCells(t *testing.T) {
+	_, err := encodeBoard([][]byte{[]byte("E?")})
+	assert.Equal(t, ErrMarshalData, err)
+
+	_, err = encodeBoard([][]byte{[]byte("EE"), []byte("E")})
+	assert.Equal(t, ErrMarshalData, err)
+}
+
+func TestDecodeBoardReadsJSON(t *testing.T) {
+	board, err := decodeBoard([]byte(`["RU0=","ZW0="]`))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("EM"), []byte("em")}, board)
+
+	board, err = decodeBoard([]byte("null"))
+	assert.Nil(t, err)
+	assert.Nil(t, board)
+}
+
+func TestDecodeBoardRejectsCorruptData(t *testing.T) {
+	data, _ := encodeBoard([][]byte{[]byte("EMe"), []byte("mXB")})
+
+	for _, corrupt := range [][]byte{
+		{},
+		{9, 1, 1, 0},
+		data[:len(data)-1],
+		append(append([]byte{}, data...), 0),
+		{boardCodecV1, 1, 1, 0xf0},
+	} {
+		_, err := decodeBoard(corrupt)
+		assert.Equal(t, ErrUnmarshalData, err, "%v", corrupt)
+	}
+}
@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+// memoryRepo keeps everything in process memory, for tests and for running
+// the API without any dependency. Data is lost when the process exits. Values
+// are copied in and out so callers never share state with the store.
+type memoryRepo struct {
+	mu           sync.RWMutex
+	users        map[string]*domain.User
+	games        map[string]*domain.Game
+	userGames    map[string]map[string]int64
+	snapshots    map[string][]*domain.Snapshot
+	moves        map[string][]*domain.Move
+	daily        map[string]map[string]*domain.DailyAttempt
+	leaderboards map[string]map[string]scoredEntry
+}
+
+type scoredEntry struct {
+	score float64
+	entry *domain.LeaderboardEntry
+}
+
+// NewMemoryRepositories returns the repositories backed by a single in-memory
+// store.
+func NewMemoryRepositories() *Repositories {
+	r := &memoryRepo{
+		users:        map[string]*domain.User{},
+		games:        map[string]*domain.Game{},
+		userGames:    map[string]map[string]int64{},
+		snapshots:    map[string][]*domain.Snapshot{},
+		moves:        map[string][]*domain.Move{},
+		daily:        map[string]map[string]*domain.DailyAttempt{},
+		leaderboards: map[string]map[string]scoredEntry{},
+	}
+	return &Repositories{Games: r, Daily: r, Leaderboards: r}
+}
+
+func (r *memoryRepo) SaveGame(game *domain.Game) (*domain.Game, error) {
+	return r.SaveGameHistory(game, nil)
+}
+
+// SaveGameHistory saves the game and changes its history under the same lock.
+func (r *memoryRepo) SaveGameHistory(game *domain.Game, history *services.GameHistory) (*domain.Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	version := int64(0)
+	if stored, ok := r.games[game.Name]; ok {
+		version = stored.Version
+	}
+	if version != game.Version {
+		return nil, services.ErrGameConflict
+	}
+
+	game.Version++
+	r.games[game.Name] = cloneGame(game)
+	if r.userGames[game.Username] == nil {
+		r.userGames[game.Username] = map[string]int64{}
+	}
+	r.userGames[game.Username][game.Name] = game.CreatedAt.UnixNano()
+
+	if history != nil {
+		r.changeHistory(game.Name, history)
+	}
+
+	return game, nil
+}
+
+func (r *memoryRepo) changeHistory(gameName string, history *services.GameHistory) {
+	if history.Reset {
+		delete(r.snapshots, gameName)
+		delete(r.moves, gameName)
+	}
+	if snapshots := r.snapshots[gameName]; history.Pop && len(snapshots) > 0 {
+		r.snapshots[gameName] = snapshots[:len(snapshots)-1]
+	}
+	if history.Push != nil {
+		stored := *history.Push
+		stored.Board = copyBoard(history.Push.Board)
+		snapshots := append(r.snapshots[gameName], &stored)
+		if len(snapshots) > history.Limit {
+			snapshots = append([]*domain.Snapshot{}, snapshots[len(snapshots)-history.Limit:]...)
+		}
+		r.snapshots[gameName] = snapshots
+	}
+	if history.Move != nil {
+		stored := *history.Move
+		r.moves[gameName] = append(r.moves[gameName], &stored)
+	}
+}
+
+func (r *memoryRepo) SaveUser(user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	version := int64(0)
+	if stored, ok := r.users[user.Username]; ok {
+		version = stored.Version
+	}
+	if version != user.Version {
+		return nil, services.ErrUserConflict
+	}
+
+	user.Version++
+	r.users[user.Username] = cloneUser(user)
+	return user, nil
+}
+
+func (r *memoryRepo) GetGame(gameName string) (*domain.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	game, ok := r.games[gameName]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return cloneGame(game), nil
+}
+
+func (r *memoryRepo) GetUser(userName string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[userName]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return cloneUser(user), nil
+}
+
+func (r *memoryRepo) UserExists(userName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.users[userName]
+	return ok
+}
+
+func (r *memoryRepo) GameExists(gameName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.games[gameName]
+	return ok
+}
+
+func (r *memoryRepo) DeleteGame(gameName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.games, gameName)
+	return nil
+}
+
+func (r *memoryRepo) GetUserGames(userName string, statuses []string, offset int, limit int) ([]*domain.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	created := r.userGames[userName]
+	names := make([]string, 0, len(created))
+	for name := range created {
+		names = append(names, name)
+	}
+	// newest first, like a reversed sorted set
+	sort.Slice(names, func(i, j int) bool {
+		if created[names[i]] != created[names[j]] {
+			return created[names[i]] > created[names[j]]
+		}
+		return names[i] > names[j]
+	})
+
+	wanted := statusSet(statuses)
+	games := []*domain.Game{}
+	for _, name := range names {
+		stored, ok := r.games[name]
+		if !ok || (len(wanted) > 0 && !wanted[stored.Status]) {
+			continue
+		}
+		game := cloneGame(stored)
+		game.Board = nil
+		games = append(games, game)
+	}
+	return pageOf(games, offset, limit), nil
+}
+
+func (r *memoryRepo) RemoveUserGame(userName string, gameName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.userGames[userName], gameName)
+	return nil
+}
+
+func (r *memoryRepo) LastSnapshot(gameName string) (*domain.Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := r.snapshots[gameName]
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	stored := *snapshots[len(snapshots)-1]
+	stored.Board = copyBoard(stored.Board)
+	return &stored, nil
+}
+
+func (r *memoryRepo) ClearSnapshots(gameName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.snapshots, gameName)
+	return nil
+}
+
+func (r *memoryRepo) GetMoves(gameName string) ([]*domain.Move, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	moves := make([]*domain.Move, 0, len(r.moves[gameName]))
+	for _, move := range r.moves[gameName] {
+		stored := *move
+		moves = append(moves, &stored)
+	}
+	return moves, nil
+}
+
+func (r *memoryRepo) ClearMoves(gameName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.moves, gameName)
+	return nil
+}
+
+func (r *memoryRepo) SaveDailyAttempt(attempt *domain.DailyAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.daily[attempt.Date] == nil {
+		r.daily[attempt.Date] = map[string]*domain.DailyAttempt{}
+	}
+	stored := *attempt
+	r.daily[attempt.Date][attempt.Username] = &stored
+	return nil
+}
+
+func (r *memoryRepo) GetDailyAttempt(date string, username string) (*domain.DailyAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attempt, ok := r.daily[date][username]
+	if !ok {
+		return nil, nil
+	}
+	stored := *attempt
+	return &stored, nil
+}
+
+func (r *memoryRepo) GetDailyAttempts(date string) ([]*domain.DailyAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attempts := make([]*domain.DailyAttempt, 0, len(r.daily[date]))
+	for _, attempt := range r.daily[date] {
+		stored := *attempt
+		attempts = append(attempts, &stored)
+	}
+	return attempts, nil
+}
+
+// AddEntry keys entries by game and finish time like the Redis repository, so
+// a restarted game can rank more than once.
+func (r *memoryRepo) AddEntry(leaderboard string, entry *domain.LeaderboardEntry, score float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leaderboards[leaderboard] == nil {
+		r.leaderboards[leaderboard] = map[string]scoredEntry{}
+	}
+	stored := *entry
+	member := fmt.Sprintf("%s@%d", entry.Game, entry.FinishedAt.UnixNano())
+	r.leaderboards[leaderboard][member] = scoredEntry{score: score, entry: &stored}
+	return nil
+}
+
+func (r *memoryRepo) GetEntries(leaderboard string, offset int, limit int) ([]*domain.LeaderboardEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]string, 0, len(r.leaderboards[leaderboard]))
+	for member := range r.leaderboards[leaderboard] {
+		members = append(members, member)
+	}
+	// lowest score first, ties in member order like a sorted set
+	scored := r.leaderboards[leaderboard]
+	sort.Slice(members, func(i, j int) bool {
+		if scored[members[i]].score != scored[members[j]].score {
+			return scored[members[i]].score < scored[members[j]].score
+		}
+		return members[i] < members[j]
+	})
+
+	entries := []*domain.LeaderboardEntry{}
+	for i := offset; i < len(members) && i < offset+limit; i++ {
+		stored := *scored[members[i]].entry
+		entries = append(entries, &stored)
+	}
+	return entries, nil
+}
+
+func cloneGame(game *domain.Game) *domain.Game {
+	stored := *game
+	stored.Board = copyBoard(game.Board)
+	if game.Players != nil {
+		stored.Players = append([]string{}, game.Players...)
+	}
+	return &stored
+}
+
+func cloneUser(user *domain.User) *domain.User {
+	stored := *user
+	if user.Stats.BestTimes != nil {
+		stored.Stats.BestTimes = make(map[string]time.Duration, len(user.Stats.BestTimes))
+		for difficulty, best := range user.Stats.BestTimes {
+			stored.Stats.BestTimes[difficulty] = best
+		}
+	}
+	return &stored
+}
+
+func copyBoard(board [][]byte) [][]byte {
+	if board == nil {
+		return nil
+	}
+	result := make([][]byte, len(board))
+	for i := range board {
+		result[i] = append([]byte{}, board[i]...)
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/repository/repositorytest"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+func TestMemoryGameRepository(t *testing.T) {
+	repositorytest.TestGameRepository(t, func(t *testing.T) services.GameRepository {
+		return NewMemoryRepositories().Games
+	})
+}
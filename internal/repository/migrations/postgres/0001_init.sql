@@ -0,0 +1,54 @@
+CREATE TABLE users (
+    username TEXT PRIMARY KEY,
+    data     JSONB NOT NULL
+);
+
+CREATE TABLE games (
+    name             TEXT PRIMARY KEY,
+    username         TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    created_at_nanos BIGINT NOT NULL,
+    version          BIGINT NOT NULL,
+    data             JSONB NOT NULL
+);
+
+-- games of a user, newest first
+CREATE INDEX games_username_created_idx ON games (username, created_at_nanos DESC, name DESC);
+
+CREATE TABLE boards (
+    game_name TEXT PRIMARY KEY REFERENCES games (name) ON DELETE CASCADE,
+    data      BYTEA NOT NULL
+);
+
+CREATE TABLE snapshots (
+    id        BIGSERIAL PRIMARY KEY,
+    game_name TEXT NOT NULL,
+    data      JSONB NOT NULL
+);
+
+CREATE INDEX snapshots_game_idx ON snapshots (game_name, id);
+
+CREATE TABLE moves (
+    id        BIGSERIAL PRIMARY KEY,
+    game_name TEXT NOT NULL,
+    data      JSONB NOT NULL
+);
+
+CREATE INDEX moves_game_idx ON moves (game_name, id);
+
+CREATE TABLE daily_attempts (
+    date     TEXT NOT NULL,
+    username TEXT NOT NULL,
+    data     JSONB NOT NULL,
+    PRIMARY KEY (date, username)
+);
+
+CREATE TABLE leaderboard_entries (
+    leaderboard TEXT NOT NULL,
+    member      TEXT NOT NULL,
+    score       DOUBLE PRECISION NOT NULL,
+    data        JSONB NOT NULL,
+    PRIMARY KEY (leaderboard, member)
+);
+
+CREATE INDEX leaderboard_entries_rank_idx ON leaderboard_entries (leaderboard, score, member);
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var postgresDialect = sqlDialect{
+	name:         "postgres",
+	placeholders: func(n int) string { return "$" + strconv.Itoa(n) },
+}
+
+// NewPostgresRepositories returns the repositories backed by the PostgreSQL
+// database at dsn, after bringing its schema up to date.
+func NewPostgresRepositories(dsn string) (*Repositories, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrate(db, postgresDialect); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	r := &sqlRepo{db: db, dialect: postgresDialect}
+	return &Repositories{Games: r, Daily: r, Leaderboards: r}, nil
+}
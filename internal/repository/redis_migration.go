@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/gomodule/redigo/redis"
+)
+
+// SchemaVersionKey holds the version of the keyspace layout, so migrations
+// only run once.
+const SchemaVersionKey = "schema:version"
+
+const namespacedKeysVersion = 1
+
+// keys used before users and games got their own keyspaces, when both were
+// stored under their bare name
+const (
+	legacyBoardSuffix              = "-Board"
+	legacyUndoSuffix               = "-Undo"
+	legacyMovesSuffix              = "-Moves"
+	legacyUserGamesPrefix          = "UserGames-"
+	legacyDailyPrefix              = "Daily-"
+	legacyLeaderboardPrefix        = "Leaderboard-"
+	legacyLeaderboardEntriesSuffix = "-Entries"
+)
+
+// MigrateRedisKeyspace moves the data stored under bare names to the
+// namespaced keys (user:{name}, game:{name}, game:{name}:board...). It does
+// nothing once the keyspace is up to date.
+func MigrateRedisKeyspace() error {
+	r := &redisRepo{pool: newRedisPool()}
+	defer r.pool.Close()
+
+	return r.migrateKeyspace()
+}
+
+func (r *redisRepo) migrateKeyspace() error {
+	conn := r.getConn()
+	defer conn.Close()
+
+	version, err := redis.Int(conn.Do("GET", SchemaVersionKey))
+	if err != nil && err != redis.ErrNil {
+		return err
+	}
+	if version >= namespacedKeysVersion {
+		return nil
+	}
+
+	// collect every key first, renaming while scanning can visit keys twice
+	var keys []string
+	cursor := 0
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "COUNT", 1000))
+		if err != nil {
+			return err
+		}
+		if cursor, err = redis.Int(values[0], nil); err != nil {
+			return err
+		}
+		batch, err := redis.Strings(values[1], nil)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, batch...)
+		if cursor == 0 {
+			break
+		}
+	}
+
+	migrated := 0
+	for _, key := range keys {
+		target, game, err := legacyKeyTarget(conn, key)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		renamed, err := redis.Int(conn.Do("RENAMENX", key, target))
+		if err != nil {
+			return err
+		}
+		if renamed == 0 {
+			log.Printf("Warning: key [%s] not migrated, [%s] already exists", key, target)
+			continue
+		}
+		migrated++
+
+		// games saved before the per-user index existed are indexed now
+		if game != nil {
+			if _, err := conn.Do("ZADD", userKey(game.Username)+UserGamesSuffix, game.CreatedAt.UnixNano(), game.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := conn.Do("SET", SchemaVersionKey, namespacedKeysVersion); err != nil {
+		return err
+	}
+
+	log.Printf("Migrated %d keys to the namespaced keyspace", migrated)
+	return nil
+}
+
+// legacyKeyTarget returns the namespaced key of a key of the old layout, or an
+// empty string for keys that are already namespaced or unknown. Users and
+// games were both plain JSON strings, they are told apart by their content.
+// Games are returned too so they can be indexed under their owner.
+func legacyKeyTarget(conn redis.Conn, key string) (string, *domain.Game, error) {
+	for _, prefix := range []string{UserPrefix, GamePrefix, DailyPrefix, LeaderboardPrefix, "schema:"} {
+		if strings.HasPrefix(key, prefix) {
+			return "", nil, nil
+		}
+	}
+
+	kind, err := redis.String(conn.Do("TYPE", key))
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch kind {
+	case "hash":
+		if strings.HasPrefix(key, legacyDailyPrefix) {
+			return DailyPrefix + strings.TrimPrefix(key, legacyDailyPrefix), nil, nil
+		}
+		if strings.HasPrefix(key, legacyLeaderboardPrefix) && strings.HasSuffix(key, legacyLeaderboardEntriesSuffix) {
+			name := strings.TrimSuffix(strings.TrimPrefix(key, legacyLeaderboardPrefix), legacyLeaderboardEntriesSuffix)
+			return LeaderboardPrefix + name + LeaderboardEntriesSuffix, nil, nil
+		}
+	case "zset":
+		if strings.HasPrefix(key, legacyUserGamesPrefix) {
+			return userKey(strings.TrimPrefix(key, legacyUserGamesPrefix)) + UserGamesSuffix, nil, nil
+		}
+		if strings.HasPrefix(key, legacyLeaderboardPrefix) {
+			return LeaderboardPrefix + strings.TrimPrefix(key, legacyLeaderboardPrefix), nil, nil
+		}
+	case "list":
+		if strings.HasSuffix(key, legacyUndoSuffix) {
+			return gameKey(strings.TrimSuffix(key, legacyUndoSuffix)) + UndoSuffix, nil, nil
+		}
+		if strings.HasSuffix(key, legacyMovesSuffix) {
+			return gameKey(strings.TrimSuffix(key, legacyMovesSuffix)) + MovesSuffix, nil, nil
+		}
+	case "string":
+		data, err := redis.Bytes(conn.Do("GET", key))
+		if err != nil {
+			return "", nil, err
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return "", nil, nil
+		}
+
+		switch fields := value.(type) {
+		case []interface{}:
+			if strings.HasSuffix(key, legacyBoardSuffix) {
+				return gameKey(strings.TrimSuffix(key, legacyBoardSuffix)) + BoardSuffix, nil, nil
+			}
+		case map[string]interface{}:
+			if _, ok := fields["rows"]; ok {
+				var game domain.Game
+				if err := json.Unmarshal(data, &game); err != nil {
+					return "", nil, ErrUnmarshalData
+				}
+				return gameKey(key), &game, nil
+			}
+			if _, ok := fields["createdAt"]; ok {
+				return userKey(key), nil, nil
+			}
+		}
+	}
+
+	return "", nil, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateKeyspace(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	server.Set("alice", `{"username":"alice","createdAt":"2020-06-11T13:03:30Z"}`)
+	server.Set("game1", `{"name":"game1","username":"alice","rows":2,"cols":2,"mines":1,"status":"ready","created_at":"2020-06-11T13:04:30Z"}`)
+	server.Set("game1-Board", `["RU0=","RU0="]`)
+	server.RPush("game1-Undo", `{"clicks":0}`)
+	server.RPush("game1-Moves", `{"kind":"click"}`)
+	server.HSet("Daily-2026-10-18", "alice", `{"username":"alice"}`)
+	server.ZAdd("Leaderboard-beginner", 10, "game1@1")
+	server.HSet("Leaderboard-beginner-Entries", "game1@1", `{"game":"game1"}`)
+	server.Set("unrelated", "value")
+
+	assert.Nil(t, repo.migrateKeyspace())
+
+	user, err := repo.GetUser("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", user.Username)
+
+	game, err := repo.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", game.Username)
+	assert.Equal(t, [][]byte{[]byte("EM"), []byte("EM")}, game.Board)
+
+	moves, err := repo.GetMoves("game1")
+	assert.Nil(t, err)
+	assert.Len(t, moves, 1)
+
+	games, err := repo.GetUserGames("alice", nil, 0, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, games, 1) {
+		assert.Equal(t, "game1", games[0].Name)
+	}
+
+	assert.True(t, server.Exists("game:game1:undo"))
+	assert.True(t, server.Exists("daily:2026-10-18"))
+	assert.True(t, server.Exists("leaderboard:beginner"))
+	assert.True(t, server.Exists("leaderboard:beginner:entries"))
+	assert.True(t, server.Exists("unrelated"))
+	assert.False(t, server.Exists("alice"))
+	assert.False(t, repo.GameExists("alice"))
+	assert.False(t, repo.UserExists("game1"))
+
+	// a second run finds the keyspace up to date
+	server.Set("bob", `{"username":"bob","createdAt":"2020-06-11T13:03:30Z"}`)
+	assert.Nil(t, repo.migrateKeyspace())
+	assert.True(t, server.Exists("bob"))
+}
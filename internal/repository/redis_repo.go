@@ -13,23 +13,41 @@ import (
 	"github.com/gomodule/redigo/redis"
 )
 
-const BoardSuffix = "-Board"
+// users and games live in separate keyspaces, the data of a game is kept
+// next to it: game:{name}, game:{name}:board, game:{name}:undo...
+const (
+	UserPrefix      = "user:"
+	UserGamesSuffix = ":games"
+
+	GamePrefix  = "game:"
+	BoardSuffix = ":board"
+	UndoSuffix  = ":undo"
+	MovesSuffix = ":moves"
+
+	DailyPrefix = "daily:"
+
+	LeaderboardPrefix        = "leaderboard:"
+	LeaderboardEntriesSuffix = ":entries"
+)
 
 var (
-	ErrDeleteBoard   = errors.New("error deleting game board")
 	ErrMarshalData   = errors.New("unable to marshal data")
 	ErrUnmarshalData = errors.New("unable to unmarshal data")
-	ErrGameNotFound  = errors.New("game not found")
+	ErrGameNotFound  = services.ErrGameNotFound
+	ErrUserNotFound  = services.ErrUserNotFound
 )
 
 type redisRepo struct {
 	pool *redis.Pool
 }
 
-func NewRedisRepository() services.GameRepository {
-	return &redisRepo{
+// NewRedisRepositories returns the repositories backed by the Redis server
+// at REDIS_URL, sharing a connection pool.
+func NewRedisRepositories() *Repositories {
+	r := &redisRepo{
 		pool: newRedisPool(),
 	}
+	return &Repositories{Games: r, Daily: r, Leaderboards: r}
 }
 
 func (r *redisRepo) getConn() redis.Conn {
@@ -37,32 +55,117 @@ func (r *redisRepo) getConn() redis.Conn {
 }
 
 func (r *redisRepo) SaveGame(game *domain.Game) (*domain.Game, error) {
+	return r.SaveGameHistory(game, nil)
+}
+
+// SaveGameHistory writes the game, its board, its entry in the index of its
+// owner and the change to its history in a single transaction, so readers
+// never see a game with a board or a history from another save. The game key
+// is watched from the version check on, the transaction is discarded if
+// another save gets in between.
+func (r *redisRepo) SaveGameHistory(game *domain.Game, history *services.GameHistory) (*domain.Game, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	k := game.Name + BoardSuffix
-	if err := r.Delete(k); err != nil {
-		return nil, ErrDeleteBoard
+	k := gameKey(game.Name)
+	if _, err := conn.Do("WATCH", k); err != nil {
+		return nil, err
+	}
+	defer conn.Do("UNWATCH")
+
+	version, err := storedVersion(conn, k)
+	if err != nil {
+		return nil, err
+	}
+	if version != game.Version {
+		return nil, services.ErrGameConflict
 	}
-	if err := r.saveBoard(k, game.Board); err != nil {
+
+	boardData, err := encodeBoard(game.Board)
+	if err != nil {
 		return nil, err
 	}
 
-	jData, err := json.Marshal(game)
+	// the board is kept under its own key only
+	stored := *game
+	stored.Board = nil
+	stored.Version++
+	jData, err := json.Marshal(&stored)
 	if err != nil {
 		log.Printf("Error: Unable to marshal game data: %q", err)
 		return nil, ErrMarshalData
 	}
 
-	_, err = conn.Do("SET", game.Name, jData)
-	return game, err
+	var snapshotData, moveData []byte
+	if history != nil && history.Push != nil {
+		if snapshotData, err = json.Marshal(history.Push); err != nil {
+			log.Printf("Error: Unable to marshal snapshot data: %q", err)
+			return nil, ErrMarshalData
+		}
+	}
+	if history != nil && history.Move != nil {
+		if moveData, err = json.Marshal(history.Move); err != nil {
+			log.Printf("Error: Unable to marshal move data: %q", err)
+			return nil, ErrMarshalData
+		}
+	}
+
+	conn.Send("MULTI")
+	conn.Send("SET", k+BoardSuffix, boardData)
+	conn.Send("SET", k, jData)
+	// index the game under its owner, newest first when read back
+	conn.Send("ZADD", userKey(game.Username)+UserGamesSuffix, game.CreatedAt.UnixNano(), game.Name)
+	if history != nil {
+		if history.Reset {
+			conn.Send("DEL", k+UndoSuffix, k+MovesSuffix)
+		}
+		if history.Pop {
+			conn.Send("RPOP", k+UndoSuffix)
+		}
+		if snapshotData != nil {
+			conn.Send("RPUSH", k+UndoSuffix, snapshotData)
+			conn.Send("LTRIM", k+UndoSuffix, -history.Limit, -1)
+		}
+		if moveData != nil {
+			conn.Send("RPUSH", k+MovesSuffix, moveData)
+		}
+	}
+	if err := execTransaction(conn, services.ErrGameConflict); err != nil {
+		return nil, err
+	}
+
+	game.Version = stored.Version
+	return game, nil
+}
+
+// storedVersion returns the version of the game or user stored at key, 0 when
+// there is none.
+func storedVersion(conn redis.Conn, key string) (int64, error) {
+	data, err := redis.Bytes(conn.Do("GET", key))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var stored struct {
+		Version int64 `json:"version"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return 0, ErrUnmarshalData
+	}
+	return stored.Version, nil
 }
 
-func (r *redisRepo) GetUser(key string) (*domain.User, error) {
+func (r *redisRepo) GetUser(userName string) (*domain.User, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	data, err := redis.String(conn.Do("GET", key))
+	data, err := redis.String(conn.Do("GET", userKey(userName)))
+	if err == redis.ErrNil {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -75,49 +178,87 @@ func (r *redisRepo) GetUser(key string) (*domain.User, error) {
 	return &user, nil
 }
 
+// SaveUser checks the version of the user like SaveGame does.
 func (r *redisRepo) SaveUser(user *domain.User) (*domain.User, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	jData, err := json.Marshal(user)
+	k := userKey(user.Username)
+	if _, err := conn.Do("WATCH", k); err != nil {
+		return nil, err
+	}
+	defer conn.Do("UNWATCH")
+
+	version, err := storedVersion(conn, k)
+	if err != nil {
+		return nil, err
+	}
+	if version != user.Version {
+		return nil, services.ErrUserConflict
+	}
+
+	stored := *user
+	stored.Version++
+	jData, err := json.Marshal(&stored)
 	if err != nil {
 		log.Printf("Error: Unable to marshal data: %q", err)
 		return nil, ErrMarshalData
 	}
 
-	_, err = conn.Do("SET", user.Username, jData)
-	return user, err
+	conn.Send("MULTI")
+	conn.Send("SET", k, jData)
+	if err := execTransaction(conn, services.ErrUserConflict); err != nil {
+		return nil, err
+	}
+
+	user.Version = stored.Version
+	return user, nil
 }
 
-func (r *redisRepo) GetGame(key string) (*domain.Game, error) {
+// GetGame reads the game and its board with a single command, which Redis
+// runs atomically with respect to SaveGame.
+func (r *redisRepo) GetGame(gameName string) (*domain.Game, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	if !r.Exists(key) {
-		return nil, ErrGameNotFound
-	}
-
-	data, err := redis.String(conn.Do("GET", key))
+	k := gameKey(gameName)
+	values, err := redis.ByteSlices(conn.Do("MGET", k, k+BoardSuffix))
 	if err != nil {
 		return nil, err
 	}
+	if values[0] == nil {
+		return nil, ErrGameNotFound
+	}
 
 	var game domain.Game
-	if err := json.Unmarshal([]byte(data), &game); err != nil {
+	if err := json.Unmarshal(values[0], &game); err != nil {
 		return nil, ErrUnmarshalData
 	}
 
-	k := key + BoardSuffix
-	board, err := r.readBoard(k)
-	if err != nil {
-		return nil, err
+	if values[1] != nil {
+		board, err := decodeBoard(values[1])
+		if err != nil {
+			return nil, err
+		}
+		game.Board = board
 	}
 
-	game.Board = board
 	return &game, nil
 }
 
-func (r *redisRepo) Exists(key string) bool {
+func (r *redisRepo) UserExists(userName string) bool {
+	return r.exists(userKey(userName))
+}
+
+func (r *redisRepo) GameExists(gameName string) bool {
+	return r.exists(gameKey(gameName))
+}
+
+func (r *redisRepo) DeleteGame(gameName string) error {
+	return r.del(gameKey(gameName), gameKey(gameName)+BoardSuffix)
+}
+
+func (r *redisRepo) exists(key string) bool {
 	conn := r.getConn()
 	defer conn.Close()
 
@@ -129,45 +270,261 @@ func (r *redisRepo) Exists(key string) bool {
 	return data > 0
 }
 
-func (r *redisRepo) Delete(key string) error {
+func (r *redisRepo) del(keys ...string) error {
 	conn := r.getConn()
 	defer conn.Close()
 
-	_, err := redis.Int(conn.Do("DEL", key))
+	_, err := redis.Int(conn.Do("DEL", redis.Args{}.AddFlat(keys)...))
 	return err
 }
 
-func (r *redisRepo) readBoard(key string) ([][]byte, error) {
+// GetUserGames reads the games of the page from the index of the user in a
+// single command, without their boards. Without a status filter only the page
+// of the index is read, with one the whole index is read and filtered.
+func (r *redisRepo) GetUserGames(userName string, statuses []string, offset int, limit int) ([]*domain.Game, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	sData, err := redis.String(conn.Do("GET", key))
+	start, stop := offset, offset+limit-1
+	if len(statuses) > 0 {
+		start, stop = 0, -1
+	}
+	names, err := redis.Strings(conn.Do("ZREVRANGE", userKey(userName)+UserGamesSuffix, start, stop))
 	if err != nil {
 		return nil, err
 	}
+	if len(names) == 0 {
+		return []*domain.Game{}, nil
+	}
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = gameKey(name)
+	}
+	values, err := redis.ByteSlices(conn.Do("MGET", redis.Args{}.AddFlat(keys)...))
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := statusSet(statuses)
+	games := []*domain.Game{}
+	for _, data := range values {
+		// deleted since it was indexed
+		if data == nil {
+			continue
+		}
+		var game domain.Game
+		if err := json.Unmarshal(data, &game); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		if len(wanted) > 0 && !wanted[game.Status] {
+			continue
+		}
+		games = append(games, &game)
+	}
+
+	if len(statuses) > 0 {
+		return pageOf(games, offset, limit), nil
+	}
+	return games, nil
+}
+
+func (r *redisRepo) RemoveUserGame(userName string, gameName string) error {
+	conn := r.getConn()
+	defer conn.Close()
+
+	_, err := conn.Do("ZREM", userKey(userName)+UserGamesSuffix, gameName)
+	return err
+}
+
+// execTransaction runs the queued commands and reports the first one that
+// failed, Redis does not report them as an error of EXEC itself. A transaction
+// discarded because a watched key changed is reported as the conflict given.
+func execTransaction(conn redis.Conn, conflict error) error {
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err == redis.ErrNil {
+		return conflict
+	}
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if err, ok := reply.(redis.Error); ok {
+			return err
+		}
+	}
+	return nil
+}
 
-	var slcData [][]byte
-	if err := json.Unmarshal([]byte(sData), &slcData); err != nil {
+func (r *redisRepo) LastSnapshot(gameName string) (*domain.Snapshot, error) {
+	conn := r.getConn()
+	defer conn.Close()
+
+	data, err := redis.Bytes(conn.Do("LINDEX", gameKey(gameName)+UndoSuffix, -1))
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot domain.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
 		return nil, ErrUnmarshalData
 	}
 
-	return slcData, nil
+	return &snapshot, nil
+}
+
+func (r *redisRepo) ClearSnapshots(gameName string) error {
+	return r.del(gameKey(gameName) + UndoSuffix)
 }
 
-func (r *redisRepo) saveBoard(key string, data [][]byte) error {
+func (r *redisRepo) GetMoves(gameName string) ([]*domain.Move, error) {
 	conn := r.getConn()
 	defer conn.Close()
 
-	boardData, err := json.Marshal(data)
+	values, err := redis.Strings(conn.Do("LRANGE", gameKey(gameName)+MovesSuffix, 0, -1))
 	if err != nil {
-		log.Printf("Error: Unable to marshal board data: %q", err)
+		return nil, err
+	}
+
+	moves := make([]*domain.Move, 0, len(values))
+	for _, data := range values {
+		var move domain.Move
+		if err := json.Unmarshal([]byte(data), &move); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		moves = append(moves, &move)
+	}
+
+	return moves, nil
+}
+
+func (r *redisRepo) ClearMoves(gameName string) error {
+	return r.del(gameKey(gameName) + MovesSuffix)
+}
+
+func (r *redisRepo) SaveDailyAttempt(attempt *domain.DailyAttempt) error {
+	conn := r.getConn()
+	defer conn.Close()
+
+	jData, err := json.Marshal(attempt)
+	if err != nil {
+		log.Printf("Error: Unable to marshal daily attempt data: %q", err)
 		return ErrMarshalData
 	}
 
-	_, err = conn.Do("SET", key, boardData)
+	_, err = conn.Do("HSET", DailyPrefix+attempt.Date, attempt.Username, jData)
 	return err
 }
 
+func (r *redisRepo) GetDailyAttempt(date string, username string) (*domain.DailyAttempt, error) {
+	conn := r.getConn()
+	defer conn.Close()
+
+	data, err := redis.String(conn.Do("HGET", DailyPrefix+date, username))
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var attempt domain.DailyAttempt
+	if err := json.Unmarshal([]byte(data), &attempt); err != nil {
+		return nil, ErrUnmarshalData
+	}
+
+	return &attempt, nil
+}
+
+func (r *redisRepo) GetDailyAttempts(date string) ([]*domain.DailyAttempt, error) {
+	conn := r.getConn()
+	defer conn.Close()
+
+	values, err := redis.StringMap(conn.Do("HGETALL", DailyPrefix+date))
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*domain.DailyAttempt, 0, len(values))
+	for _, data := range values {
+		var attempt domain.DailyAttempt
+		if err := json.Unmarshal([]byte(data), &attempt); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, nil
+}
+
+// AddEntry ranks the entry in a sorted set, the entry itself is kept in a hash
+// next to it. Both are keyed by game and finish time so a restarted game can
+// rank more than once.
+func (r *redisRepo) AddEntry(leaderboard string, entry *domain.LeaderboardEntry, score float64) error {
+	conn := r.getConn()
+	defer conn.Close()
+
+	jData, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error: Unable to marshal leaderboard entry data: %q", err)
+		return ErrMarshalData
+	}
+
+	member := fmt.Sprintf("%s@%d", entry.Game, entry.FinishedAt.UnixNano())
+	key := LeaderboardPrefix + leaderboard
+	// the entry and its rank are saved together, so a ranked member always has an entry
+	conn.Send("MULTI")
+	conn.Send("HSET", key+LeaderboardEntriesSuffix, member, jData)
+	conn.Send("ZADD", key, score, member)
+	return execTransaction(conn, nil)
+}
+
+func (r *redisRepo) GetEntries(leaderboard string, offset int, limit int) ([]*domain.LeaderboardEntry, error) {
+	conn := r.getConn()
+	defer conn.Close()
+
+	key := LeaderboardPrefix + leaderboard
+	members, err := redis.Strings(conn.Do("ZRANGE", key, offset, offset+limit-1))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.LeaderboardEntry, 0, len(members))
+	if len(members) == 0 {
+		return entries, nil
+	}
+
+	values, err := redis.ByteSlices(conn.Do("HMGET", redis.Args{}.Add(key+LeaderboardEntriesSuffix).AddFlat(members)...))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data := range values {
+		// members ranked before entries were saved along with them may have none
+		if data == nil {
+			continue
+		}
+		var entry domain.LeaderboardEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+func userKey(userName string) string {
+	return UserPrefix + userName
+}
+
+func gameKey(gameName string) string {
+	return GamePrefix + gameName
+}
+
 func newRedisPool() *redis.Pool {
 	redisURL := os.Getenv("REDIS_URL")
 	return &redis.Pool{
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/repository/repositorytest"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRepo(t *testing.T) (*redisRepo, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", server.Addr())
+		},
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	return &redisRepo{pool: pool}, server
+}
+
+func TestRedisGameRepository(t *testing.T) {
+	repositorytest.TestGameRepository(t, func(t *testing.T) services.GameRepository {
+		repo, _ := newTestRepo(t)
+		return repo
+	})
+}
+
+func TestSaveAndGetGame(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	game := &domain.Game{Name: "game1", Username: "alice", Rows: 2, Cols: 2, Mines: 1, Status: "ready", Board: [][]byte{[]byte("EM"), []byte("EE")}, CreatedAt: time.Now()}
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	// the board is only stored under its own key
+	var stored map[string]interface{}
+	data, _ := server.Get("game:game1")
+	assert.Nil(t, json.Unmarshal([]byte(data), &stored))
+	assert.NotContains(t, stored, "board")
+
+	saved, err := repo.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, game.Board, saved.Board)
+	assert.Equal(t, "alice", saved.Username)
+
+	games, err := repo.GetUserGames("alice", nil, 0, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, games, 1) {
+		assert.Equal(t, "game1", games[0].Name)
+	}
+
+	_, err = repo.GetGame("missing")
+	assert.Equal(t, ErrGameNotFound, err)
+
+	assert.Nil(t, repo.DeleteGame("game1"))
+	assert.False(t, repo.GameExists("game1"))
+	assert.False(t, server.Exists("game:game1:board"))
+}
+
+func TestSaveGameReportsFailedCommands(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	server.Set("user:alice:games", "not a sorted set")
+	_, err := repo.SaveGame(&domain.Game{Name: "game1", Username: "alice", Board: [][]byte{[]byte("EM")}})
+	assert.NotNil(t, err)
+}
+
+func TestGetGameReadsLegacyBoardInGameRecord(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	server.Set("game:game1", `{"name":"game1","username":"alice","rows":1,"cols":2,"board":["RU0="]}`)
+	game, err := repo.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("EM")}, game.Board)
+}
+
+func TestGetGameReadsJSONBoard(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	// saved before boards were stored in binary
+	server.Set("game:game1", `{"name":"game1","username":"alice","rows":1,"cols":2,"version":1}`)
+	server.Set("game:game1:board", `["RW0="]`)
+	game, err := repo.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("Em")}, game.Board)
+
+	// the next save rewrites it in binary
+	_, err = repo.SaveGame(game)
+	assert.Nil(t, err)
+	data, _ := server.Get("game:game1:board")
+	assert.Equal(t, byte(boardCodecV1), data[0])
+
+	game, err = repo.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("Em")}, game.Board)
+}
+
+func TestHistoryKeys(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	game := &domain.Game{Name: "game1", Username: "alice", Board: [][]byte{[]byte("EM")}, UndoLimit: 2}
+	for clicks := 1; clicks <= 3; clicks++ {
+		_, err := repo.SaveGameHistory(game, &services.GameHistory{Push: &domain.Snapshot{Clicks: clicks}, Limit: 2, Move: &domain.Move{Kind: "flag"}})
+		assert.Nil(t, err)
+	}
+
+	// the history lives next to the game, the undo history trimmed to its limit
+	snapshots, err := server.List("game:game1:undo")
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 2)
+	moves, err := server.List("game:game1:moves")
+	assert.Nil(t, err)
+	assert.Len(t, moves, 3)
+
+	snapshot, err := repo.LastSnapshot("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, snapshot.Clicks)
+
+	assert.Nil(t, repo.ClearSnapshots("game1"))
+	assert.False(t, server.Exists("game:game1:undo"))
+	snapshot, err = repo.LastSnapshot("game1")
+	assert.Nil(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestSaveGameComparesVersions(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	game := &domain.Game{Name: "game1", Username: "alice", Board: [][]byte{[]byte("EM")}}
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), game.Version)
+
+	first, _ := repo.GetGame("game1")
+	second, _ := repo.GetGame("game1")
+	first.Clicks++
+	_, err = repo.SaveGame(first)
+	assert.Nil(t, err)
+
+	// the second copy was read before the first one was saved
+	second.Hints++
+	_, err = repo.SaveGame(second)
+	assert.Equal(t, services.ErrGameConflict, err)
+
+	saved, _ := repo.GetGame("game1")
+	assert.Equal(t, int64(2), saved.Version)
+	assert.Equal(t, 1, saved.Clicks)
+	assert.Equal(t, 0, saved.Hints)
+}
+
+func TestLeaderboardSkipsMembersWithoutEntry(t *testing.T) {
+	repo, server := newTestRepo(t)
+
+	finishedAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	for i, game := range []string{"game1", "game2", "game3"} {
+		entry := &domain.LeaderboardEntry{Game: game, Username: "alice", FinishedAt: finishedAt}
+		assert.Nil(t, repo.AddEntry("beginner", entry, float64(i)))
+	}
+	members, err := server.ZMembers("leaderboard:beginner")
+	assert.Nil(t, err)
+	assert.Len(t, members, 3)
+
+	// a member ranked without its entry does not break the page
+	server.HDel("leaderboard:beginner:entries", members[1])
+
+	entries, err := repo.GetEntries("beginner", 0, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "game1", entries[0].Game)
+		assert.Equal(t, "game3", entries[1].Game)
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+// Repositories groups the repositories of a storage backend. They share the
+// same store, daily attempts and leaderboard entries refer to its games.
+type Repositories struct {
+	Games        services.GameRepository
+	Daily        services.DailyRepository
+	Leaderboards services.LeaderboardRepository
+}
+
+// statusSet is the set of the statuses a listing is filtered on, empty when it
+// is not filtered.
+func statusSet(statuses []string) map[string]bool {
+	wanted := map[string]bool{}
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+	return wanted
+}
+
+// pageOf cuts the page starting at offset out of a list of games.
+func pageOf(games []*domain.Game, offset int, limit int) []*domain.Game {
+	if offset >= len(games) {
+		return []*domain.Game{}
+	}
+	games = games[offset:]
+	if len(games) > limit {
+		games = games[:limit]
+	}
+	return games
+}
@@ -0,0 +1,505 @@
+// Package repositorytest checks that an implementation of
+// services.GameRepository behaves the way the services rely on, so every
+// storage backend can be run against the same expectations.
+package repositorytest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrentSavers is the number of goroutines saving the same game in the
+// concurrency tests.
+const concurrentSavers = 10
+
+// TestGameRepository runs the conformance tests against the repository
+// returned by newRepo, which is called once per test. Users and games get
+// unique names, so repositories may share a store between tests and runs.
+func TestGameRepository(t *testing.T, newRepo func(t *testing.T) services.GameRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo services.GameRepository)
+	}{
+		{"SaveAndGetGame", testSaveAndGetGame},
+		{"SaveGameUpdates", testSaveGameUpdates},
+		{"SaveGameConflicts", testSaveGameConflicts},
+		{"SaveAndGetUser", testSaveAndGetUser},
+		{"SaveUserConflicts", testSaveUserConflicts},
+		{"Exists", testExists},
+		{"DeleteGame", testDeleteGame},
+		{"MissingKeys", testMissingKeys},
+		{"UserGames", testUserGames},
+		{"Snapshots", testSnapshots},
+		{"SnapshotLimit", testSnapshotLimit},
+		{"Moves", testMoves},
+		{"HistoryFollowsTheGame", testHistoryFollowsTheGame},
+		{"ConcurrentSaves", testConcurrentSaves},
+		{"ConcurrentCreates", testConcurrentCreates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+func uniqueName(prefix string) string {
+	return prefix + "-" + ksuid.New().String()
+}
+
+// newGame returns a game with every field set, times in UTC so they read back
+// equal from any encoding.
+func newGame(owner string) *domain.Game {
+	createdAt := time.Date(2026, 10, 18, 10, 30, 0, 123456789, time.UTC)
+	return &domain.Game{
+		Name:             uniqueName("game"),
+		Username:         owner,
+		Players:          []string{"bob", "carol"},
+		Rows:             3,
+		Cols:             4,
+		Mines:            2,
+		Difficulty:       "custom",
+		FirstClick:       "safe",
+		NoGuess:          true,
+		Seed:             42,
+		Mode:             "casual",
+		Status:           "in_progress",
+		Board:            [][]byte{[]byte("EMEE"), []byte("1meB"), []byte("XeEm")},
+		Clicks:           5,
+		Hints:            1,
+		UndoLimit:        3,
+		Undos:            1,
+		CreatedAt:        createdAt,
+		StartedAt:        createdAt.Add(time.Second),
+		TimeSpent:        90 * time.Second,
+		ThreeBV:          7,
+		ThreeBVPerSecond: 0.5,
+		Efficiency:       0.75,
+	}
+}
+
+func testSaveAndGetGame(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	board := [][]byte{[]byte("EMEE"), []byte("1meB"), []byte("XeEm")}
+
+	saved, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), saved.Version)
+	assert.Equal(t, board, game.Board, "the board of the caller is left as is")
+
+	stored, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, game, stored)
+}
+
+func testSaveGameUpdates(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	game.Status = "won"
+	game.Clicks++
+	game.Board[0][0] = '1'
+	_, err = repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	stored, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, "won", stored.Status)
+	assert.Equal(t, 6, stored.Clicks)
+	assert.Equal(t, byte('1'), stored.Board[0][0])
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func testSaveGameConflicts(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	stale, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	_, err = repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	stale.Status = "over"
+	_, err = repo.SaveGame(stale)
+	assert.Equal(t, services.ErrGameConflict, err)
+
+	// a new game cannot replace one with the same name
+	recreated := newGame(game.Username)
+	recreated.Name = game.Name
+	_, err = repo.SaveGame(recreated)
+	assert.Equal(t, services.ErrGameConflict, err)
+
+	stored, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, game.Status, stored.Status)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func testSaveAndGetUser(t *testing.T, repo services.GameRepository) {
+	user := &domain.User{
+		Username:  uniqueName("alice"),
+		CreatedAt: time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC),
+		Stats: domain.UserStats{
+			GamesPlayed:       3,
+			Wins:              2,
+			Losses:            1,
+			WinRate:           2.0 / 3,
+			CurrentStreak:     1,
+			LongestStreak:     2,
+			BestTimes:         map[string]time.Duration{"beginner": 42 * time.Second},
+			AverageEfficiency: 0.8,
+		},
+	}
+
+	saved, err := repo.SaveUser(user)
+	assert.Nil(t, err)
+	assert.Equal(t, user, saved)
+
+	stored, err := repo.GetUser(user.Username)
+	assert.Nil(t, err)
+	assert.Equal(t, user, stored)
+
+	user.Stats.Wins = 3
+	_, err = repo.SaveUser(user)
+	assert.Nil(t, err)
+	stored, err = repo.GetUser(user.Username)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, stored.Stats.Wins)
+}
+
+func testSaveUserConflicts(t *testing.T, repo services.GameRepository) {
+	user := &domain.User{Username: uniqueName("alice"), CreatedAt: time.Now().UTC()}
+	_, err := repo.SaveUser(user)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), user.Version)
+
+	stale, err := repo.GetUser(user.Username)
+	assert.Nil(t, err)
+	user.Stats.Wins = 1
+	_, err = repo.SaveUser(user)
+	assert.Nil(t, err)
+
+	stale.Stats.Losses = 1
+	_, err = repo.SaveUser(stale)
+	assert.Equal(t, services.ErrUserConflict, err)
+
+	// a new user cannot replace one with the same name
+	_, err = repo.SaveUser(&domain.User{Username: user.Username})
+	assert.Equal(t, services.ErrUserConflict, err)
+
+	stored, err := repo.GetUser(user.Username)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stored.Stats.Wins)
+	assert.Equal(t, 0, stored.Stats.Losses)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func testExists(t *testing.T, repo services.GameRepository) {
+	name := uniqueName("same")
+	assert.False(t, repo.UserExists(name))
+	assert.False(t, repo.GameExists(name))
+
+	_, err := repo.SaveUser(&domain.User{Username: name, CreatedAt: time.Now().UTC()})
+	assert.Nil(t, err)
+	assert.True(t, repo.UserExists(name))
+	assert.False(t, repo.GameExists(name), "users and games do not share names")
+
+	game := newGame(name)
+	game.Name = name
+	_, err = repo.SaveGame(game)
+	assert.Nil(t, err)
+	assert.True(t, repo.GameExists(name))
+
+	_, err = repo.GetUser(name)
+	assert.Nil(t, err, "saving a game does not overwrite the user of the same name")
+}
+
+func testDeleteGame(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	assert.Nil(t, repo.DeleteGame(game.Name))
+	assert.False(t, repo.GameExists(game.Name))
+	_, err = repo.GetGame(game.Name)
+	assert.Equal(t, services.ErrGameNotFound, err)
+
+	assert.Nil(t, repo.DeleteGame(game.Name), "deleting a missing game is not an error")
+
+	// the name is free again, a new game starts from version 0
+	recreated := newGame(game.Username)
+	recreated.Name = game.Name
+	recreated.Board = [][]byte{[]byte("EE"), []byte("EM")}
+	_, err = repo.SaveGame(recreated)
+	assert.Nil(t, err)
+
+	stored, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, recreated.Board, stored.Board)
+	assert.Equal(t, int64(1), stored.Version)
+}
+
+func testMissingKeys(t *testing.T, repo services.GameRepository) {
+	game, err := repo.GetGame(uniqueName("missing"))
+	assert.Nil(t, game)
+	assert.True(t, errors.Is(err, services.ErrGameNotFound), "got %v", err)
+
+	user, err := repo.GetUser(uniqueName("missing"))
+	assert.Nil(t, user)
+	assert.True(t, errors.Is(err, services.ErrUserNotFound), "got %v", err)
+}
+
+func testUserGames(t *testing.T, repo services.GameRepository) {
+	owner := uniqueName("alice")
+
+	assert.Empty(t, userGames(t, repo, owner, nil, 0, 10))
+
+	older, newer, newest := newGame(owner), newGame(owner), newGame(owner)
+	newer.CreatedAt = older.CreatedAt.Add(time.Minute)
+	newest.CreatedAt = older.CreatedAt.Add(2 * time.Minute)
+	newest.Status = "won"
+	for _, game := range []*domain.Game{older, newer, newest, newGame(uniqueName("bob"))} {
+		_, err := repo.SaveGame(game)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, []string{newest.Name, newer.Name, older.Name}, userGames(t, repo, owner, nil, 0, 10))
+
+	// listings are paged and filtered on the status
+	assert.Equal(t, []string{newer.Name}, userGames(t, repo, owner, nil, 1, 1))
+	assert.Empty(t, userGames(t, repo, owner, nil, 3, 10))
+	assert.Equal(t, []string{newer.Name, older.Name}, userGames(t, repo, owner, []string{"in_progress"}, 0, 10))
+	assert.Equal(t, []string{older.Name}, userGames(t, repo, owner, []string{"in_progress"}, 1, 10))
+	assert.Equal(t, []string{newest.Name, newer.Name}, userGames(t, repo, owner, []string{"won", "in_progress"}, 0, 2))
+	assert.Empty(t, userGames(t, repo, owner, []string{"over"}, 0, 10))
+
+	// games are listed without their boards
+	games, err := repo.GetUserGames(owner, nil, 0, 1)
+	assert.Nil(t, err)
+	if assert.Len(t, games, 1) {
+		assert.Nil(t, games[0].Board)
+		assert.Equal(t, "won", games[0].Status)
+		assert.Equal(t, owner, games[0].Username)
+	}
+
+	// saving again does not list a game twice
+	_, err = repo.SaveGame(older)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{newest.Name, newer.Name, older.Name}, userGames(t, repo, owner, nil, 0, 10))
+
+	assert.Nil(t, repo.DeleteGame(newer.Name))
+	assert.Nil(t, repo.RemoveUserGame(owner, newer.Name))
+	assert.Equal(t, []string{newest.Name, older.Name}, userGames(t, repo, owner, nil, 0, 10))
+}
+
+// userGames lists the names of a page of the games of a user.
+func userGames(t *testing.T, repo services.GameRepository, userName string, statuses []string, offset int, limit int) []string {
+	games, err := repo.GetUserGames(userName, statuses, offset, limit)
+	assert.Nil(t, err)
+	names := []string{}
+	for _, game := range games {
+		names = append(names, game.Name)
+	}
+	return names
+}
+
+// saveHistory saves the game along with a change to its history.
+func saveHistory(t *testing.T, repo services.GameRepository, game *domain.Game, history *services.GameHistory) {
+	_, err := repo.SaveGameHistory(game, history)
+	assert.Nil(t, err)
+}
+
+// lastClicks returns the clicks of the last snapshot of a game, -1 when its
+// undo history is empty.
+func lastClicks(t *testing.T, repo services.GameRepository, gameName string) int {
+	snapshot, err := repo.LastSnapshot(gameName)
+	assert.Nil(t, err)
+	if snapshot == nil {
+		return -1
+	}
+	return snapshot.Clicks
+}
+
+func testSnapshots(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	saveHistory(t, repo, game, nil)
+
+	snapshot, err := repo.LastSnapshot(game.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, snapshot)
+
+	for clicks := 1; clicks <= 3; clicks++ {
+		saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Board: [][]byte{[]byte("EM")}, Clicks: clicks, Status: "in_progress"}, Limit: 3})
+	}
+
+	snapshot, err = repo.LastSnapshot(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, &domain.Snapshot{Board: [][]byte{[]byte("EM")}, Clicks: 3, Status: "in_progress"}, snapshot)
+	assert.Equal(t, 3, lastClicks(t, repo, game.Name), "reading the last snapshot leaves it in place")
+
+	saveHistory(t, repo, game, &services.GameHistory{Pop: true})
+	assert.Equal(t, 2, lastClicks(t, repo, game.Name))
+
+	assert.Nil(t, repo.ClearSnapshots(game.Name))
+	assert.Equal(t, -1, lastClicks(t, repo, game.Name))
+
+	// popping an empty history is not an error
+	saveHistory(t, repo, game, &services.GameHistory{Pop: true})
+}
+
+func testSnapshotLimit(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+
+	for clicks := 1; clicks <= 5; clicks++ {
+		saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: clicks}, Limit: 2})
+	}
+
+	// only the newest ones are kept
+	for _, clicks := range []int{5, 4, -1} {
+		assert.Equal(t, clicks, lastClicks(t, repo, game.Name))
+		saveHistory(t, repo, game, &services.GameHistory{Pop: true})
+	}
+
+	// a lower limit trims what was kept before
+	saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: 6}, Limit: 3})
+	saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: 7}, Limit: 3})
+	saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: 8}, Limit: 1})
+	assert.Equal(t, 8, lastClicks(t, repo, game.Name))
+	saveHistory(t, repo, game, &services.GameHistory{Pop: true})
+	assert.Equal(t, -1, lastClicks(t, repo, game.Name))
+}
+
+func testMoves(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	saveHistory(t, repo, game, nil)
+
+	moves, err := repo.GetMoves(game.Name)
+	assert.Nil(t, err)
+	assert.Empty(t, moves)
+
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	logged := []*domain.Move{
+		{Row: 0, Col: 1, Kind: "flag", Status: "in_progress", At: at},
+		{Row: 1, Col: 1, Kind: "click", Status: "over", At: at.Add(time.Second)},
+	}
+	for _, move := range logged {
+		saveHistory(t, repo, game, &services.GameHistory{Move: move})
+	}
+
+	moves, err = repo.GetMoves(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, logged, moves)
+
+	assert.Nil(t, repo.ClearMoves(game.Name))
+	moves, err = repo.GetMoves(game.Name)
+	assert.Nil(t, err)
+	assert.Empty(t, moves)
+}
+
+// testHistoryFollowsTheGame checks the history is only changed by the saves
+// of the game that go through.
+func testHistoryFollowsTheGame(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: 1}, Limit: 5, Move: &domain.Move{Kind: "click", Status: "in_progress"}})
+
+	stale, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	saveHistory(t, repo, game, &services.GameHistory{Push: &domain.Snapshot{Clicks: 2}, Limit: 5, Move: &domain.Move{Kind: "flag", Status: "in_progress"}})
+
+	_, err = repo.SaveGameHistory(stale, &services.GameHistory{Pop: true, Move: &domain.Move{Kind: "undo", Status: "in_progress"}})
+	assert.Equal(t, services.ErrGameConflict, err)
+	assert.Equal(t, 2, lastClicks(t, repo, game.Name))
+	moves, err := repo.GetMoves(game.Name)
+	assert.Nil(t, err)
+	if assert.Len(t, moves, 2) {
+		assert.Equal(t, "flag", moves[1].Kind)
+	}
+
+	// a restarted game starts over with no history
+	saveHistory(t, repo, game, &services.GameHistory{Reset: true, Move: &domain.Move{Kind: "click", Status: "in_progress"}})
+	assert.Equal(t, -1, lastClicks(t, repo, game.Name))
+	moves, err = repo.GetMoves(game.Name)
+	assert.Nil(t, err)
+	assert.Len(t, moves, 1)
+}
+
+// testConcurrentSaves updates the same game from several goroutines, each one
+// reading it again after a conflict. No update may be lost.
+func testConcurrentSaves(t *testing.T, repo services.GameRepository) {
+	game := newGame(uniqueName("alice"))
+	game.Clicks = 0
+	_, err := repo.SaveGame(game)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentSavers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				current, err := repo.GetGame(game.Name)
+				if !assert.Nil(t, err) {
+					return
+				}
+				current.Clicks++
+				_, err = repo.SaveGame(current)
+				if err == services.ErrGameConflict {
+					continue
+				}
+				assert.Nil(t, err)
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, err := repo.GetGame(game.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, concurrentSavers, stored.Clicks)
+	assert.Equal(t, int64(concurrentSavers+1), stored.Version)
+}
+
+// testConcurrentCreates creates the same game from several goroutines, only
+// one of them may succeed.
+func testConcurrentCreates(t *testing.T, repo services.GameRepository) {
+	name := uniqueName("game")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentSavers)
+	for i := 0; i < concurrentSavers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			game := newGame(uniqueName("alice"))
+			game.Name = name
+			_, err := repo.SaveGame(game)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.Equal(t, services.ErrGameConflict, err)
+	}
+	assert.Equal(t, 1, created)
+
+	stored, err := repo.GetGame(name)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), stored.Version)
+}
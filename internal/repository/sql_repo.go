@@ -0,0 +1,443 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/services"
+)
+
+// sqlDialect holds what differs between the SQL databases: the directory of
+// their migrations and how query parameters are written. Queries are written
+// with ? placeholders and rebound for the database.
+type sqlDialect struct {
+	name         string
+	placeholders func(n int) string
+}
+
+func (d sqlDialect) rebind(query string) string {
+	if d.placeholders == nil {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString(d.placeholders(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// sqlRepo stores users, games and their boards in relational tables. Records
+// that are only read whole are kept as JSON next to the columns they are
+// looked up by.
+type sqlRepo struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+func (r *sqlRepo) exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.db.Exec(r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepo) queryRow(query string, args ...interface{}) *sql.Row {
+	return r.db.QueryRow(r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepo) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.Query(r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepo) SaveGame(game *domain.Game) (*domain.Game, error) {
+	return r.SaveGameHistory(game, nil)
+}
+
+// SaveGameHistory writes the game, its board and the change to its history
+// in a transaction. The version check is part of the write: a new game is only
+// inserted if there is none with its name, an existing one only updated if it
+// is still at the version read.
+func (r *sqlRepo) SaveGameHistory(game *domain.Game, history *services.GameHistory) (*domain.Game, error) {
+	boardData, err := encodeBoard(game.Board)
+	if err != nil {
+		return nil, err
+	}
+
+	// the board is kept in its own table only
+	stored := *game
+	stored.Board = nil
+	stored.Version++
+	jData, err := json.Marshal(&stored)
+	if err != nil {
+		log.Printf("Error: Unable to marshal game data: %q", err)
+		return nil, ErrMarshalData
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if game.Version == 0 {
+		result, err = tx.Exec(r.dialect.rebind(`INSERT INTO games (name, username, status, created_at_nanos, version, data)
+			VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (name) DO NOTHING`),
+			game.Name, game.Username, game.Status, game.CreatedAt.UnixNano(), stored.Version, string(jData))
+	} else {
+		result, err = tx.Exec(r.dialect.rebind(`UPDATE games SET username = ?, status = ?, created_at_nanos = ?, version = ?, data = ?
+			WHERE name = ? AND version = ?`),
+			game.Username, game.Status, game.CreatedAt.UnixNano(), stored.Version, string(jData), game.Name, game.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, services.ErrGameConflict
+	}
+
+	if _, err := tx.Exec(r.dialect.rebind(`INSERT INTO boards (game_name, data) VALUES (?, ?)
+		ON CONFLICT (game_name) DO UPDATE SET data = excluded.data`), game.Name, boardData); err != nil {
+		return nil, err
+	}
+
+	if history != nil {
+		if err := r.changeHistory(tx, game.Name, history); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	game.Version = stored.Version
+	return game, nil
+}
+
+// SaveUser inserts a new user or updates one still at the version read, in a
+// single statement. Users stored before they had versions are at version 0.
+func (r *sqlRepo) SaveUser(user *domain.User) (*domain.User, error) {
+	stored := *user
+	stored.Version++
+	jData, err := json.Marshal(&stored)
+	if err != nil {
+		log.Printf("Error: Unable to marshal data: %q", err)
+		return nil, ErrMarshalData
+	}
+
+	result, err := r.exec(`INSERT INTO users (username, version, data) VALUES (?, ?, ?)
+		ON CONFLICT (username) DO UPDATE SET version = excluded.version, data = excluded.data
+		WHERE users.version = ?`, user.Username, stored.Version, string(jData), user.Version)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, services.ErrUserConflict
+	}
+
+	user.Version = stored.Version
+	return user, nil
+}
+
+// GetGame reads the game and its board with a single query, so both come
+// from the same save.
+func (r *sqlRepo) GetGame(gameName string) (*domain.Game, error) {
+	var data, boardData []byte
+	err := r.queryRow(`SELECT g.data, b.data FROM games g LEFT JOIN boards b ON b.game_name = g.name
+		WHERE g.name = ?`, gameName).Scan(&data, &boardData)
+	if err == sql.ErrNoRows {
+		return nil, ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var game domain.Game
+	if err := json.Unmarshal(data, &game); err != nil {
+		return nil, ErrUnmarshalData
+	}
+
+	if boardData != nil {
+		board, err := decodeBoard(boardData)
+		if err != nil {
+			return nil, err
+		}
+		game.Board = board
+	}
+
+	return &game, nil
+}
+
+func (r *sqlRepo) GetUser(userName string) (*domain.User, error) {
+	var data []byte
+	err := r.queryRow("SELECT data FROM users WHERE username = ?", userName).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var user domain.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, ErrUnmarshalData
+	}
+
+	return &user, nil
+}
+
+func (r *sqlRepo) UserExists(userName string) bool {
+	return r.exists("SELECT 1 FROM users WHERE username = ?", userName)
+}
+
+func (r *sqlRepo) GameExists(gameName string) bool {
+	return r.exists("SELECT 1 FROM games WHERE name = ?", gameName)
+}
+
+func (r *sqlRepo) exists(query string, args ...interface{}) bool {
+	var found int
+	return r.queryRow(query, args...).Scan(&found) == nil
+}
+
+// DeleteGame removes the game, its board goes along with it.
+func (r *sqlRepo) DeleteGame(gameName string) error {
+	_, err := r.exec("DELETE FROM games WHERE name = ?", gameName)
+	return err
+}
+
+// GetUserGames reads the page of the games owned by the user, filtered and
+// cut by the database along its games_username_created_idx index. The index
+// is the games table itself, so there is nothing to remove from it in
+// RemoveUserGame.
+func (r *sqlRepo) GetUserGames(userName string, statuses []string, offset int, limit int) ([]*domain.Game, error) {
+	query := "SELECT data FROM games WHERE username = ?"
+	args := []interface{}{userName}
+	if len(statuses) > 0 {
+		query += " AND status IN (?" + strings.Repeat(", ?", len(statuses)-1) + ")"
+		for _, status := range statuses {
+			args = append(args, status)
+		}
+	}
+	query += " ORDER BY created_at_nanos DESC, name DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []*domain.Game{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var game domain.Game
+		if err := json.Unmarshal(data, &game); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		games = append(games, &game)
+	}
+	return games, rows.Err()
+}
+
+func (r *sqlRepo) RemoveUserGame(userName string, gameName string) error {
+	return nil
+}
+
+func (r *sqlRepo) changeHistory(tx *sql.Tx, gameName string, history *services.GameHistory) error {
+	if history.Reset {
+		if _, err := tx.Exec(r.dialect.rebind("DELETE FROM snapshots WHERE game_name = ?"), gameName); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(r.dialect.rebind("DELETE FROM moves WHERE game_name = ?"), gameName); err != nil {
+			return err
+		}
+	}
+	if history.Pop {
+		if _, err := tx.Exec(r.dialect.rebind(`DELETE FROM snapshots WHERE id = (
+			SELECT MAX(id) FROM snapshots WHERE game_name = ?)`), gameName); err != nil {
+			return err
+		}
+	}
+	if history.Push != nil {
+		jData, err := json.Marshal(history.Push)
+		if err != nil {
+			log.Printf("Error: Unable to marshal snapshot data: %q", err)
+			return ErrMarshalData
+		}
+		if _, err := tx.Exec(r.dialect.rebind("INSERT INTO snapshots (game_name, data) VALUES (?, ?)"), gameName, string(jData)); err != nil {
+			return err
+		}
+		// only the last snapshots within the limit are kept
+		if _, err := tx.Exec(r.dialect.rebind(`DELETE FROM snapshots WHERE game_name = ? AND id NOT IN (
+			SELECT id FROM snapshots WHERE game_name = ? ORDER BY id DESC LIMIT ?)`), gameName, gameName, history.Limit); err != nil {
+			return err
+		}
+	}
+	if history.Move != nil {
+		jData, err := json.Marshal(history.Move)
+		if err != nil {
+			log.Printf("Error: Unable to marshal move data: %q", err)
+			return ErrMarshalData
+		}
+		if _, err := tx.Exec(r.dialect.rebind("INSERT INTO moves (game_name, data) VALUES (?, ?)"), gameName, string(jData)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sqlRepo) LastSnapshot(gameName string) (*domain.Snapshot, error) {
+	var data []byte
+	err := r.queryRow("SELECT data FROM snapshots WHERE game_name = ? ORDER BY id DESC LIMIT 1", gameName).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot domain.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, ErrUnmarshalData
+	}
+
+	return &snapshot, nil
+}
+
+func (r *sqlRepo) ClearSnapshots(gameName string) error {
+	_, err := r.exec("DELETE FROM snapshots WHERE game_name = ?", gameName)
+	return err
+}
+
+func (r *sqlRepo) GetMoves(gameName string) ([]*domain.Move, error) {
+	rows, err := r.query("SELECT data FROM moves WHERE game_name = ? ORDER BY id", gameName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	moves := []*domain.Move{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var move domain.Move
+		if err := json.Unmarshal(data, &move); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		moves = append(moves, &move)
+	}
+	return moves, rows.Err()
+}
+
+func (r *sqlRepo) ClearMoves(gameName string) error {
+	_, err := r.exec("DELETE FROM moves WHERE game_name = ?", gameName)
+	return err
+}
+
+func (r *sqlRepo) SaveDailyAttempt(attempt *domain.DailyAttempt) error {
+	jData, err := json.Marshal(attempt)
+	if err != nil {
+		log.Printf("Error: Unable to marshal daily attempt data: %q", err)
+		return ErrMarshalData
+	}
+
+	_, err = r.exec(`INSERT INTO daily_attempts (date, username, data) VALUES (?, ?, ?)
+		ON CONFLICT (date, username) DO UPDATE SET data = excluded.data`, attempt.Date, attempt.Username, string(jData))
+	return err
+}
+
+func (r *sqlRepo) GetDailyAttempt(date string, username string) (*domain.DailyAttempt, error) {
+	var data []byte
+	err := r.queryRow("SELECT data FROM daily_attempts WHERE date = ? AND username = ?", date, username).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var attempt domain.DailyAttempt
+	if err := json.Unmarshal(data, &attempt); err != nil {
+		return nil, ErrUnmarshalData
+	}
+
+	return &attempt, nil
+}
+
+func (r *sqlRepo) GetDailyAttempts(date string) ([]*domain.DailyAttempt, error) {
+	rows, err := r.query("SELECT data FROM daily_attempts WHERE date = ?", date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*domain.DailyAttempt{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var attempt domain.DailyAttempt
+		if err := json.Unmarshal(data, &attempt); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, rows.Err()
+}
+
+// AddEntry keys entries by game and finish time like the Redis repository, so
+// a restarted game can rank more than once.
+func (r *sqlRepo) AddEntry(leaderboard string, entry *domain.LeaderboardEntry, score float64) error {
+	jData, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error: Unable to marshal leaderboard entry data: %q", err)
+		return ErrMarshalData
+	}
+
+	member := fmt.Sprintf("%s@%d", entry.Game, entry.FinishedAt.UnixNano())
+	_, err = r.exec(`INSERT INTO leaderboard_entries (leaderboard, member, score, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (leaderboard, member) DO UPDATE SET score = excluded.score, data = excluded.data`,
+		leaderboard, member, score, string(jData))
+	return err
+}
+
+func (r *sqlRepo) GetEntries(leaderboard string, offset int, limit int) ([]*domain.LeaderboardEntry, error) {
+	rows, err := r.query(`SELECT data FROM leaderboard_entries WHERE leaderboard = ?
+		ORDER BY score, member LIMIT ? OFFSET ?`, leaderboard, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.LeaderboardEntry{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var entry domain.LeaderboardEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, ErrUnmarshalData
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
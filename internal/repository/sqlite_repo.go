@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"database/sql"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+var sqliteDialect = sqlDialect{name: "sqlite"}
+
+// NewSQLiteRepositories returns the repositories backed by the SQLite database
+// file at path, created if needed, after bringing its schema up to date.
+//
+// SQLite takes a single writer at a time. The pool holds one connection so
+// writers queue up in the process instead of failing with SQLITE_BUSY, and the
+// busy timeout covers other processes using the same file.
+func NewSQLiteRepositories(path string) (*Repositories, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+	pragmas.Add("_pragma", "busy_timeout(5000)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db, sqliteDialect); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	r := &sqlRepo{db: db, dialect: sqliteDialect}
+	return &Repositories{Games: r, Daily: r, Leaderboards: r}, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/repository/repositorytest"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSQLiteRepo(t *testing.T) *sqlRepo {
+	repos, err := NewSQLiteRepositories(filepath.Join(t.TempDir(), "minesweeper.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repos.Games.(*sqlRepo)
+	t.Cleanup(func() { repo.db.Close() })
+	return repo
+}
+
+func TestSQLiteGameRepository(t *testing.T) {
+	repositorytest.TestGameRepository(t, func(t *testing.T) services.GameRepository {
+		return newTestSQLiteRepo(t)
+	})
+}
+
+func TestSQLiteLeaderboardEntries(t *testing.T) {
+	testSQLLeaderboardEntries(t, newTestSQLiteRepo(t), "beginner")
+}
+
+// testSQLLeaderboardEntries checks the leaderboard of the SQL backends, which
+// the game repository conformance tests do not cover.
+func testSQLLeaderboardEntries(t *testing.T, repo *sqlRepo, leaderboard string) {
+	finishedAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	slow := &domain.LeaderboardEntry{Game: "game1", Username: "alice", FinishedAt: finishedAt}
+	fast := &domain.LeaderboardEntry{Game: "game2", Username: "bob", FinishedAt: finishedAt}
+	assert.Nil(t, repo.AddEntry(leaderboard, slow, 90))
+	assert.Nil(t, repo.AddEntry(leaderboard, fast, 30))
+	// adding an entry again updates its score instead of listing it twice
+	assert.Nil(t, repo.AddEntry(leaderboard, slow, 60))
+
+	entries, err := repo.GetEntries(leaderboard, 0, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "game2", entries[0].Game, "lower scores rank first")
+		assert.Equal(t, "game1", entries[1].Game)
+	}
+
+	entries, err = repo.GetEntries(leaderboard, 1, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "alice", entries[0].Username)
+	}
+}
+
+func TestSQLiteRepositoryMigratesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "minesweeper.db")
+	for i := 0; i < 2; i++ {
+		repos, err := NewSQLiteRepositories(path)
+		assert.Nil(t, err)
+		repos.Games.(*sqlRepo).db.Close()
+	}
+}
+
+func TestSQLiteRepositoryConcurrentWriters(t *testing.T) {
+	repo := newTestSQLiteRepo(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every writer creates the same game, only one can win and log its move
+			move := &services.GameHistory{Move: &domain.Move{Kind: "click"}}
+			_, err := repo.SaveGameHistory(&domain.Game{Name: "game1", Username: "alice", Status: "ready", CreatedAt: time.Now()}, move)
+			errs <- err
+			_, err = repo.SaveGameHistory(&domain.Game{Name: fmt.Sprintf("game-%d", i), Username: "alice", Status: "ready", CreatedAt: time.Now()}, move)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	conflicts := 0
+	for err := range errs {
+		if err != nil {
+			assert.Equal(t, services.ErrGameConflict, err)
+			conflicts++
+		}
+	}
+	assert.Equal(t, 19, conflicts)
+
+	moves, err := repo.GetMoves("game1")
+	assert.Nil(t, err)
+	assert.Len(t, moves, 1)
+
+	games, err := repo.GetUserGames("alice", nil, 0, 100)
+	assert.Nil(t, err)
+	assert.Len(t, games, 21)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	apperrors "github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameAccess(t *testing.T) {
+	service, _ := newTestService(t)
+	_, err := service.CreateUser(&domain.User{Username: "carol"})
+	assert.Nil(t, err)
+
+	_, err = service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Players: []string{"bob"}, Difficulty: "beginner", Seed: 42, UndoLimit: 10})
+	assert.Nil(t, err)
+	_, err = service.Click("game1", "alice", &domain.ClickData{Row: 4, Col: 4, Kind: "click"})
+	assert.Nil(t, err)
+
+	actions := []struct {
+		name string
+		act  func(userName string) error
+	}{
+		{"Click", func(userName string) error {
+			_, err := service.Click("game1", userName, &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+			return err
+		}},
+		{"Undo", func(userName string) error {
+			_, err := service.Undo("game1", userName)
+			return err
+		}},
+		{"Board", func(userName string) error {
+			_, err := service.Board("game1", userName)
+			return err
+		}},
+		{"Hint", func(userName string) error {
+			_, err := service.Hint("game1", userName)
+			return err
+		}},
+		{"Heatmap", func(userName string) error {
+			_, err := service.Heatmap("game1", userName)
+			return err
+		}},
+	}
+
+	for _, action := range actions {
+		t.Run(action.name, func(t *testing.T) {
+			err := action.act("carol")
+			assert.IsType(t, &apperrors.ForbiddenError{}, err, "a stranger is turned away")
+
+			assert.Nil(t, action.act("alice"), "the owner plays")
+			assert.Nil(t, action.act("bob"), "a listed player plays")
+		})
+	}
+}
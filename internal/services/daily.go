@@ -0,0 +1,213 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+const (
+	dailyDifficulty = "intermediate"
+	dailyPrefix     = "daily-"
+	dateLayout      = "2006-01-02"
+)
+
+type DailyService interface {
+	Today() (*domain.DailyChallenge, error)
+	Click(userName string, click *domain.ClickData) (*domain.Game, error)
+	Board(userName string) ([]uint8, error)
+	Leaderboard(date string) ([]*domain.DailyAttempt, error)
+}
+
+type dailyService struct {
+	games GameService
+	repo  GameRepository
+	daily DailyRepository
+	key   []byte
+	now   func() time.Time
+}
+
+// NewDailyService returns the daily challenge service. The key keeps the
+// boards of the days to come secret, it must be the one of the game service.
+func NewDailyService(games GameService, db GameRepository, daily DailyRepository, key []byte) DailyService {
+	return &dailyService{games: games, repo: db, daily: daily, key: key, now: time.Now}
+}
+
+// Today returns the challenge of the current UTC day. Every user gets the same
+// board, along with a start cell guaranteed to open an area.
+func (s *dailyService) Today() (*domain.DailyChallenge, error) {
+	date := s.today()
+	game := dailyGame(s.key, date, "")
+	row, col := startCell(game)
+
+	return &domain.DailyChallenge{
+		Date:     date,
+		Rows:     game.Rows,
+		Cols:     game.Cols,
+		Mines:    game.Mines,
+		StartRow: row,
+		StartCol: col,
+	}, nil
+}
+
+// Click plays a cell of the user daily game, creating it on the first click.
+// Each user gets a single game per day: once it is over or won it cannot be
+// played again.
+func (s *dailyService) Click(userName string, click *domain.ClickData) (*domain.Game, error) {
+	if !s.repo.UserExists(userName) {
+		return nil, errors.New("user_not_found")
+	}
+
+	date := s.today()
+	attempt, err := s.daily.GetDailyAttempt(date, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt == nil {
+		game := dailyGame(s.key, date, userName)
+		game.CreatedAt = s.now()
+		// a conflict means a concurrent first click created the game already
+		if _, err := s.repo.SaveGame(game); err != nil && err != ErrGameConflict {
+			return nil, errors.New("error saving game")
+		}
+		attempt = &domain.DailyAttempt{Date: date, Username: userName, Game: game.Name, Status: game.Status}
+		if err := s.daily.SaveDailyAttempt(attempt); err != nil {
+			return nil, err
+		}
+	}
+
+	// the games API turns daily games away, so the attempt sees every move
+	game, err := s.games.clickDaily(attempt.Game, userName, click)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.record(attempt, game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// Board returns the board of the user daily game.
+func (s *dailyService) Board(userName string) ([]uint8, error) {
+	if !s.repo.UserExists(userName) {
+		return nil, errors.New("user_not_found")
+	}
+
+	attempt, err := s.daily.GetDailyAttempt(s.today(), userName)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == nil {
+		return nil, errors.New("game_not_found")
+	}
+
+	return s.games.Board(attempt.Game, userName)
+}
+
+// Leaderboard ranks the unassisted wins of a day (today when date is empty)
+// by time spent, ties are broken by the number of clicks.
+func (s *dailyService) Leaderboard(date string) ([]*domain.DailyAttempt, error) {
+	if date == "" {
+		date = s.today()
+	}
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return nil, errors.New("bad_date")
+	}
+
+	attempts, err := s.daily.GetDailyAttempts(date)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := []*domain.DailyAttempt{}
+	for _, attempt := range attempts {
+		if attempt.Status == "won" && attempt.Verified && attempt.Hints == 0 {
+			ranking = append(ranking, attempt)
+		}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].TimeSpent != ranking[j].TimeSpent {
+			return ranking[i].TimeSpent < ranking[j].TimeSpent
+		}
+		return ranking[i].Clicks < ranking[j].Clicks
+	})
+
+	return ranking, nil
+}
+
+// record copies the outcome of the daily game into the attempt. Wins only
+// make it to the leaderboard once their replay passes verification.
+func (s *dailyService) record(attempt *domain.DailyAttempt, game *domain.Game) error {
+	attempt.Status = game.Status
+	attempt.Clicks = game.Clicks
+	attempt.Hints = game.Hints
+	attempt.TimeSpent = game.TimeSpent
+	if game.Status == "over" || game.Status == "won" {
+		attempt.FinishedAt = s.now()
+	}
+	if game.Status == "won" {
+		verification, err := s.games.Verify(attempt.Game, attempt.Username)
+		if err != nil {
+			return err
+		}
+		attempt.Verified = verification.Passed
+	}
+	return s.daily.SaveDailyAttempt(attempt)
+}
+
+func (s *dailyService) today() string {
+	return s.now().UTC().Format(dateLayout)
+}
+
+// dailyGame builds the game of a user for the challenge of the given date. The
+// board only depends on the date and the key.
+func dailyGame(key []byte, date string, userName string) *domain.Game {
+	game := &domain.Game{
+		Name:       dailyPrefix + date + "-" + userName,
+		Username:   userName,
+		Rows:       presets[dailyDifficulty].rows,
+		Cols:       presets[dailyDifficulty].cols,
+		Mines:      presets[dailyDifficulty].mines,
+		Difficulty: dailyDifficulty,
+		FirstClick: "classic",
+		Seed:       dailySeed(key, date),
+		Mode:       "daily",
+		Status:     "ready",
+	}
+	generateBoard(game)
+	return game
+}
+
+// dailySeed derives the seed of a day from the key, so the board of a day
+// cannot be worked out from the date alone.
+func dailySeed(key []byte, date string) int64 {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(dailyPrefix + date))
+	seed := int64(binary.BigEndian.Uint64(mac.Sum(nil)) &^ (1 << 63))
+	if seed == 0 {
+		seed = 1
+	}
+	return seed
+}
+
+// isDailyBoard reports whether a game would be played on the board of today
+// daily challenge, which players only get to play once.
+func isDailyBoard(key []byte, game *domain.Game, now time.Time) bool {
+	daily := presets[dailyDifficulty]
+	return game.Seed == dailySeed(key, now.UTC().Format(dateLayout)) &&
+		game.Rows == daily.rows && game.Cols == daily.cols && game.Mines == daily.mines
+}
+
+// isDailyGame reports whether the name is reserved for daily games.
+func isDailyGame(name string) bool {
+	return strings.HasPrefix(name, dailyPrefix)
+}
@@ -0,0 +1,11 @@
+package services
+
+import "github.com/arllanos/minesweeper-API/internal/domain"
+
+// DailyRepository stores the attempts users make at each daily challenge.
+// GetDailyAttempt returns nil and no error when the user has not played yet.
+type DailyRepository interface {
+	SaveDailyAttempt(attempt *domain.DailyAttempt) error
+	GetDailyAttempt(date string, username string) (*domain.DailyAttempt, error)
+	GetDailyAttempts(date string) ([]*domain.DailyAttempt, error)
+}
@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/repository"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDate = "2026-10-18"
+
+var testDailyKey = []byte("secret")
+
+func newTestDailyService(t *testing.T) (services.DailyService, services.GameService, *repository.Repositories) {
+	games, repos := newTestService(t)
+	daily := services.NewDailyService(games, repos.Games, repos.Daily, testDailyKey)
+	services.SetNow(daily, func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) })
+	return daily, games, repos
+}
+
+// findCell returns the first cell of the stored board of a game holding value.
+func findCell(t *testing.T, repos *repository.Repositories, gameName string, value byte) (int, int) {
+	game, err := repos.Games.GetGame(gameName)
+	assert.Nil(t, err)
+	for i := range game.Board {
+		for j := range game.Board[i] {
+			if game.Board[i][j] == value {
+				return i, j
+			}
+		}
+	}
+	t.Fatalf("no %q cell on game %s", value, gameName)
+	return -1, -1
+}
+
+func TestDailyToday(t *testing.T) {
+	daily, _, _ := newTestDailyService(t)
+
+	challenge, err := daily.Today()
+	assert.Nil(t, err)
+	assert.Equal(t, testDate, challenge.Date)
+	assert.Equal(t, 16, challenge.Rows)
+	assert.Equal(t, 40, challenge.Mines)
+}
+
+func TestDailyClickCreatesAttemptOnFirstClick(t *testing.T) {
+	daily, _, repos := newTestDailyService(t)
+
+	_, err := daily.Board("alice")
+	assert.EqualError(t, err, "game_not_found")
+
+	challenge, _ := daily.Today()
+	game, err := daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.Nil(t, err)
+	assert.Equal(t, "daily-2026-10-18-alice", game.Name)
+	assert.Equal(t, "in_progress", game.Status)
+
+	attempt, err := repos.Daily.GetDailyAttempt(testDate, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, game.Name, attempt.Game)
+	assert.Equal(t, "in_progress", attempt.Status)
+	assert.Equal(t, 1, attempt.Clicks)
+
+	_, err = daily.Board("alice")
+	assert.Nil(t, err)
+
+	_, err = daily.Click("nobody", &domain.ClickData{Kind: "click"})
+	assert.EqualError(t, err, "user_not_found")
+}
+
+func TestDailyClickAfterConcurrentFirstClick(t *testing.T) {
+	daily, _, repos := newTestDailyService(t)
+
+	// another first click saved the game but not the attempt yet
+	game := services.DailyGame(testDailyKey, testDate, "alice")
+	_, err := repos.Games.SaveGame(game)
+	assert.Nil(t, err)
+
+	challenge, _ := daily.Today()
+	played, err := daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.Nil(t, err)
+	assert.Equal(t, game.Name, played.Name)
+
+	attempt, err := repos.Daily.GetDailyAttempt(testDate, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, game.Name, attempt.Game)
+}
+
+func TestDailyIsPlayedOnce(t *testing.T) {
+	daily, _, repos := newTestDailyService(t)
+
+	challenge, _ := daily.Today()
+	_, err := daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.Nil(t, err)
+
+	row, col := findCell(t, repos, "daily-2026-10-18-alice", 'M')
+	game, err := daily.Click("alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+
+	_, err = daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.EqualError(t, err, "game_over")
+
+	attempt, err := repos.Daily.GetDailyAttempt(testDate, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "over", attempt.Status)
+	assert.False(t, attempt.FinishedAt.IsZero())
+}
+
+func TestDailyGameIsOnlyPlayedThroughTheDailyService(t *testing.T) {
+	daily, games, repos := newTestDailyService(t)
+	name := "daily-2026-10-18-alice"
+
+	challenge, _ := daily.Today()
+	_, err := daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.Nil(t, err)
+
+	// the attempt would not follow moves made through the games API
+	row, col := findCell(t, repos, name, 'M')
+	_, err = games.Click(name, "alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+	assert.EqualError(t, err, "daily_game")
+	_, err = games.Hint(name, "alice")
+	assert.EqualError(t, err, "daily_game")
+	_, err = games.Heatmap(name, "alice")
+	assert.EqualError(t, err, "daily_game")
+	_, err = games.Undo(name, "alice")
+	assert.EqualError(t, err, "daily_game")
+
+	game, err := repos.Games.GetGame(name)
+	assert.Nil(t, err)
+	assert.Equal(t, "in_progress", game.Status)
+	assert.Zero(t, game.Hints)
+
+	_, err = daily.Click("alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+	assert.Nil(t, err)
+	attempt, err := repos.Daily.GetDailyAttempt(testDate, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "over", attempt.Status)
+}
+
+func TestDailyWinRecordsVerification(t *testing.T) {
+	daily, games, repos := newTestDailyService(t)
+	name := "daily-2026-10-18-alice"
+
+	challenge, _ := daily.Today()
+	game, err := daily.Click("alice", &domain.ClickData{Row: challenge.StartRow, Col: challenge.StartCol, Kind: "click"})
+	assert.Nil(t, err)
+
+	for game.Status != "won" {
+		row, col := findCell(t, repos, name, 'E')
+		game, err = daily.Click("alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	attempt, err := repos.Daily.GetDailyAttempt(testDate, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "won", attempt.Status)
+	assert.Equal(t, game.Clicks, attempt.Clicks)
+
+	verification, err := games.Verify(name, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, verification.Passed, attempt.Verified)
+}
+
+func TestDailyLeaderboard(t *testing.T) {
+	daily, _, repos := newTestDailyService(t)
+
+	for _, attempt := range []*domain.DailyAttempt{
+		{Username: "slow", Status: "won", Verified: true, TimeSpent: 90 * time.Second, Clicks: 10},
+		{Username: "fast", Status: "won", Verified: true, TimeSpent: 30 * time.Second, Clicks: 20},
+		{Username: "tidy", Status: "won", Verified: true, TimeSpent: 30 * time.Second, Clicks: 15},
+		{Username: "assisted", Status: "won", Verified: true, Hints: 1, TimeSpent: time.Second},
+		{Username: "forged", Status: "won", TimeSpent: time.Second},
+		{Username: "lost", Status: "over", Verified: true, TimeSpent: time.Second},
+		{Username: "playing", Status: "in_progress"},
+	} {
+		attempt.Date = testDate
+		assert.Nil(t, repos.Daily.SaveDailyAttempt(attempt))
+	}
+	assert.Nil(t, repos.Daily.SaveDailyAttempt(&domain.DailyAttempt{Date: "2026-10-17", Username: "yesterday", Status: "won", Verified: true}))
+
+	ranking, err := daily.Leaderboard("")
+	assert.Nil(t, err)
+	names := []string{}
+	for _, attempt := range ranking {
+		names = append(names, attempt.Username)
+	}
+	assert.Equal(t, []string{"tidy", "fast", "slow"}, names)
+
+	ranking, err = daily.Leaderboard("2026-10-17")
+	assert.Nil(t, err)
+	assert.Len(t, ranking, 1)
+
+	_, err = daily.Leaderboard("yesterday")
+	assert.EqualError(t, err, "bad_date")
+}
+
+func TestCreateGameRejectsDailyBoard(t *testing.T) {
+	service, _ := newTestService(t)
+	seed := services.DailySeed(testDailyKey, time.Now().UTC().Format("2006-01-02"))
+
+	_, err := service.CreateGame(&domain.Game{Username: "alice", Difficulty: "intermediate", Seed: seed})
+	assert.EqualError(t, err, "reserved_seed")
+
+	_, err = service.CreateGame(&domain.Game{Username: "alice", Difficulty: "intermediate", Seed: seed + 1})
+	assert.Nil(t, err)
+}
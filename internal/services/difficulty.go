@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	apperrors "github.com/arllanos/minesweeper-API/internal/errors"
+)
+
+type preset struct {
+	rows  int
+	cols  int
+	mines int
+}
+
+// maxNoGuessMinePercent bounds the mine density of no guess games, above it
+// layouts that can be solved without guessing get too rare to be found
+const maxNoGuessMinePercent = 22
+
+// named difficulty presets, any other configuration is "custom"
+var presets = map[string]preset{
+	"beginner":     {rows: 9, cols: 9, mines: 10},
+	"intermediate": {rows: 16, cols: 16, mines: 40},
+	"expert":       {rows: 16, cols: 30, mines: 99},
+}
+
+// applyDifficulty validates the board configuration of a new game and fills it
+// in from its difficulty preset or from the defaults. Nothing is rewritten: an
+// invalid configuration is rejected with the details of every failing field.
+func applyDifficulty(game *domain.Game) error {
+	verr := &apperrors.ValidationError{}
+
+	if p, ok := presets[game.Difficulty]; ok {
+		if game.Rows != 0 && game.Rows != p.rows {
+			verr.Add("rows", fmt.Sprintf("must be omitted or %d for %s difficulty", p.rows, game.Difficulty))
+		}
+		if game.Cols != 0 && game.Cols != p.cols {
+			verr.Add("cols", fmt.Sprintf("must be omitted or %d for %s difficulty", p.cols, game.Difficulty))
+		}
+		if game.Mines != 0 && game.Mines != p.mines {
+			verr.Add("mines", fmt.Sprintf("must be omitted or %d for %s difficulty", p.mines, game.Difficulty))
+		}
+		game.Rows, game.Cols, game.Mines = p.rows, p.cols, p.mines
+	} else if game.Difficulty == "" || game.Difficulty == "custom" {
+		// defaults
+		if game.Rows == 0 {
+			game.Rows = defaultRows
+		}
+		if game.Cols == 0 {
+			game.Cols = defaultCols
+		}
+		if game.Mines == 0 {
+			game.Mines = defaultMines
+		}
+
+		if game.Rows < minRows || game.Rows > maxRows {
+			verr.Add("rows", fmt.Sprintf("must be between %d and %d", minRows, maxRows))
+		}
+		if game.Cols < minCols || game.Cols > maxCols {
+			verr.Add("cols", fmt.Sprintf("must be between %d and %d", minCols, maxCols))
+		}
+		// at least one mine and one cell free of mines
+		if cells := game.Rows * game.Cols; game.Mines < 1 || game.Mines >= cells {
+			verr.Add("mines", fmt.Sprintf("must be between 1 and %d", cells-1))
+		}
+
+		game.Difficulty = "custom"
+		for name, p := range presets {
+			if game.Rows == p.rows && game.Cols == p.cols && game.Mines == p.mines {
+				game.Difficulty = name
+			}
+		}
+	} else {
+		verr.Add("difficulty", "must be one of beginner, intermediate, expert or custom")
+	}
+
+	if game.UndoLimit < 0 {
+		verr.Add("undo_limit", "must be zero (undo disabled) or positive")
+	}
+
+	// no guess boards are generated around the first click
+	if game.NoGuess && game.FirstClick == "" {
+		game.FirstClick = "opening"
+	}
+	if game.FirstClick != "" && game.FirstClick != "classic" && !deferredMines(game) {
+		verr.Add("first_click", "must be one of classic, safe or opening")
+	} else if game.NoGuess && !deferredMines(game) {
+		verr.Add("first_click", "must be safe or opening for no guess games")
+	}
+
+	// the first click of opening games and its neighbours are kept free of
+	// mines, wherever it lands there must be room for the mines around them
+	if area := min(game.Rows, 3) * min(game.Cols, 3); game.FirstClick == "opening" && game.Mines > game.Rows*game.Cols-area {
+		verr.Add("mines", fmt.Sprintf("must be at most %d for opening first clicks", game.Rows*game.Cols-area))
+	}
+
+	if cells := game.Rows * game.Cols; game.NoGuess && game.Mines*100 > cells*maxNoGuessMinePercent {
+		verr.Add("mines", fmt.Sprintf("must be at most %d (%d%% of the cells) for no guess games", cells*maxNoGuessMinePercent/100, maxNoGuessMinePercent))
+	}
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+	return nil
+}
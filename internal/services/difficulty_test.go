@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	apperrors "github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDifficultyPreset(t *testing.T) {
+	game := domain.Game{Difficulty: "expert"}
+
+	err := applyDifficulty(&game)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 16, game.Rows)
+	assert.Equal(t, 30, game.Cols)
+	assert.Equal(t, 99, game.Mines)
+}
+
+func TestApplyDifficultyRejectsInvalidConfig(t *testing.T) {
+	game := domain.Game{Rows: 40, Cols: 4, Mines: 160, FirstClick: "lucky"}
+
+	err := applyDifficulty(&game)
+
+	verr, ok := err.(*apperrors.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"rows", "mines", "first_click"}, fieldNames(verr))
+	assert.Equal(t, 40, game.Rows)
+}
+
+func TestApplyDifficultyRecognizesPresetDimensions(t *testing.T) {
+	game := domain.Game{Rows: 9, Cols: 9, Mines: 10}
+
+	err := applyDifficulty(&game)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "beginner", game.Difficulty)
+}
+
+func TestApplyDifficultyBoundsNoGuessDensity(t *testing.T) {
+	game := domain.Game{Rows: 30, Cols: 30, Mines: 400, NoGuess: true}
+
+	err := applyDifficulty(&game)
+
+	verr, ok := err.(*apperrors.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"mines"}, fieldNames(verr))
+
+	// expert density is fine
+	game = domain.Game{Difficulty: "expert", NoGuess: true}
+	assert.Nil(t, applyDifficulty(&game))
+}
+
+func TestApplyDifficultyLeavesRoomForOpenings(t *testing.T) {
+	game := domain.Game{Rows: 5, Cols: 5, Mines: 17, FirstClick: "opening"}
+
+	err := applyDifficulty(&game)
+
+	verr, ok := err.(*apperrors.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"mines"}, fieldNames(verr))
+
+	game = domain.Game{Rows: 5, Cols: 5, Mines: 16, FirstClick: "opening"}
+	assert.Nil(t, applyDifficulty(&game))
+	// safe first clicks only need the clicked cell
+	game = domain.Game{Rows: 5, Cols: 5, Mines: 24, FirstClick: "safe"}
+	assert.Nil(t, applyDifficulty(&game))
+}
+
+func fieldNames(verr *apperrors.ValidationError) []string {
+	var names []string
+	for _, f := range verr.Fields {
+		names = append(names, f.Field)
+	}
+	return names
+}
@@ -0,0 +1,15 @@
+package services
+
+import "time"
+
+// hooks for the tests of services_test, which can use the repositories
+
+var (
+	DailySeed = dailySeed
+	DailyGame = dailyGame
+)
+
+// SetNow replaces the clock of a daily service.
+func SetNow(daily DailyService, now func() time.Time) {
+	daily.(*dailyService).now = now
+}
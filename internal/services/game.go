@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/arllanos/minesweeper-API/internal/domain"
+	apperrors "github.com/arllanos/minesweeper-API/internal/errors"
 	"github.com/segmentio/ksuid"
 )
 
@@ -18,161 +21,557 @@ const (
 	maxCols      = 30
 	minRows      = 2
 	minCols      = 2
+
+	// read-modify-write attempts of a game before reporting a conflict
+	maxSaveAttempts = 3
 )
 
+// user and game names end up in storage keys, they are kept to characters no
+// backend uses as a separator so a name never maps onto the keys of another
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+var gameStatuses = map[string]bool{"ready": true, "in_progress": true, "over": true, "won": true}
+
 type GameService interface {
 	CreateGame(game *domain.Game) (*domain.Game, error)
 	CreateUser(user *domain.User) (*domain.User, error)
-	Exists(key string) bool
+	UserExists(userName string) bool
+	GameExists(gameName string) bool
 	Click(gameName string, userName string, data *domain.ClickData) (*domain.Game, error)
+	Undo(gameName string, userName string) (*domain.Game, error)
+	Resign(gameName string, userName string) (*domain.Game, error)
+	Moves(gameName string, userName string) ([]*domain.Move, error)
+	Replay(gameName string, userName string, n int) (*domain.Game, error)
+	Verify(gameName string, userName string) (*domain.Verification, error)
+	Stats(userName string) (*domain.UserStats, error)
+	Games(userName string, statuses []string, limit int, cursor string) (*domain.GamePage, error)
+	Game(gameName string, userName string) (*domain.Game, error)
+	DeleteGame(gameName string, userName string) error
 	Board(gameName string, userName string) ([]uint8, error)
+	DebugBoard(gameName string) ([]uint8, error)
+	Hint(gameName string, userName string) (*domain.Hint, error)
+	Heatmap(gameName string, userName string) (*domain.Heatmap, error)
+
+	// clickDaily plays a daily game, which is only played through the daily
+	// service so the attempt of the day follows it
+	clickDaily(gameName string, userName string, data *domain.ClickData) (*domain.Game, error)
 }
 
 type service struct {
-	repo GameRepository
+	repo         GameRepository
+	leaderboards LeaderboardRepository
+	dailyKey     []byte
 }
 
-func NewGameService(db GameRepository) GameService {
-	return &service{repo: db}
+// NewGameService returns the game service. The daily key is the secret the
+// daily boards are derived from, games cannot be created on today board.
+func NewGameService(db GameRepository, leaderboards LeaderboardRepository, dailyKey []byte) GameService {
+	return &service{repo: db, leaderboards: leaderboards, dailyKey: dailyKey}
 }
 
 func (s *service) CreateGame(game *domain.Game) (*domain.Game, error) {
 
-	if !s.repo.Exists(game.Username) {
+	if !s.repo.UserExists(game.Username) {
 		return nil, errors.New("user_not_found")
 	}
 
-	// defaults
-	if game.Rows == 0 {
-		game.Rows = defaultRows
-	}
-	if game.Rows == 0 {
-		game.Rows = defaultRows
-	}
-	if game.Cols == 0 {
-		game.Cols = defaultCols
-	}
-	if game.Mines == 0 {
-		game.Mines = defaultMines
+	if err := applyDifficulty(game); err != nil {
+		return nil, err
 	}
 
-	// maximum
-	if game.Rows > maxRows {
-		game.Rows = maxRows
+	// if no game name assign a short ID
+	if game.Name == "" {
+		game.Name = ksuid.New().String()
 	}
-	if game.Cols > maxCols {
-		game.Cols = maxCols
+	if !namePattern.MatchString(game.Name) {
+		return nil, errors.New("bad_game_name")
 	}
-
-	// minimum
-	if game.Rows < minRows {
-		game.Rows = minRows
+	if isDailyGame(game.Name) {
+		return nil, errors.New("reserved_game_name")
 	}
-	if game.Cols < minCols {
-		game.Cols = minCols
+	// the daily board is played once, it cannot be rehearsed in a game of its own
+	if game.Seed != 0 && isDailyBoard(s.dailyKey, game, time.Now()) {
+		return nil, errors.New("reserved_seed")
 	}
+	// a board played from a given seed may be known in advance
+	game.CustomSeed = game.Seed != 0
+	game.Mode = ""
+	game.Board = nil
+	game.Clicks = 0
+	game.Hints = 0
+	game.Undos = 0
+	game.Recorded = false
+	game.ThreeBV = 0
+	game.ThreeBVPerSecond = 0
+	game.Efficiency = 0
+	game.StartedAt = time.Time{}
+	game.TimeSpent = 0
+	game.CreatedAt = time.Now()
 
-	if game.Mines > (game.Cols * game.Rows) {
-		game.Mines = (game.Cols * game.Rows)
+	// only the owner can restart a game
+	if s.repo.GameExists(game.Name) {
+		previous, err := s.repo.GetGame(game.Name)
+		if err != nil {
+			return nil, err
+		}
+		if previous.Username != game.Username {
+			return nil, &apperrors.ForbiddenError{Game: game.Name, Username: game.Username}
+		}
+		game.Version = previous.Version
+	} else {
+		game.Version = 0
 	}
 
-	// if no game name assign a short ID
-	if game.Name == "" {
-		game.Name = ksuid.New().String()
+	// the game is shared with the other players listed, the owner is implied
+	players := []string{}
+	for _, player := range game.Players {
+		if player == game.Username || containsString(players, player) {
+			continue
+		}
+		if !s.repo.UserExists(player) {
+			return nil, errors.New("player_not_found")
+		}
+		players = append(players, player)
 	}
-	game.Board = nil
-	game.CreatedAt = time.Now()
+	game.Players = players
 
 	// start the game with an initialized board
 	game.Status = "ready"
 	generateBoard(game)
-	_, err := s.repo.SaveGame(game)
+	// a restarted game starts with no history
+	_, err := s.repo.SaveGameHistory(game, &GameHistory{Reset: true})
 
+	if err == ErrGameConflict {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.New("error saving game")
 	}
 
-	return game, err
+	return playerView(game), err
 }
 
 func (s *service) CreateUser(user *domain.User) (*domain.User, error) {
-	if s.repo.Exists(user.Username) {
+	if !namePattern.MatchString(user.Username) {
+		return nil, errors.New("bad_username")
+	}
+	if s.repo.UserExists(user.Username) {
 		return nil, errors.New("user_already_exist")
 	}
 
 	user.CreatedAt = time.Now()
-	return s.repo.SaveUser(user)
+	user.Stats = domain.UserStats{}
+	user.Version = 0
+	// a conflict means a concurrent request created the user first
+	result, err := s.repo.SaveUser(user)
+	if err == ErrUserConflict {
+		return nil, errors.New("user_already_exist")
+	}
+	return result, err
 }
 
-func (s *service) Exists(key string) bool {
-	return s.repo.Exists(key)
+func (s *service) UserExists(userName string) bool {
+	return s.repo.UserExists(userName)
 }
 
+func (s *service) GameExists(gameName string) bool {
+	return s.repo.GameExists(gameName)
+}
+
+// Click plays a cell of a game. Daily games are played through the daily
+// service instead.
 func (s *service) Click(gameName string, userName string, click *domain.ClickData) (*domain.Game, error) {
-	if !s.repo.Exists(gameName) {
-		return nil, errors.New("game_not_found")
+	return s.click(gameName, userName, click, false)
+}
+
+func (s *service) clickDaily(gameName string, userName string, click *domain.ClickData) (*domain.Game, error) {
+	return s.click(gameName, userName, click, true)
+}
+
+func (s *service) click(gameName string, userName string, click *domain.ClickData, daily bool) (*domain.Game, error) {
+	var recordStats bool
+
+	game, err := s.updateGame(gameName, userName, func(game *domain.Game) (*GameHistory, error) {
+		if game.Mode == "daily" && !daily {
+			return nil, errors.New("daily_game")
+		}
+
+		log.Printf("Click type [%s] request at (%d, %d) for game [%s] with status [%s]", click.Kind, click.Row, click.Col, game.Name, game.Status)
+
+		// state to go back to if the move is undone
+		snapshot := &domain.Snapshot{
+			Board:     copyBoard(game.Board),
+			Clicks:    game.Clicks,
+			Status:    game.Status,
+			StartedAt: game.StartedAt,
+		}
+
+		if err := applyMove(game, click); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		if snapshot.Status == "ready" {
+			// first click: set start time
+			game.StartedAt = now
+		}
+
+		game.TimeSpent = now.Sub(game.StartedAt)
+		scoreGame(game)
+
+		// the result of a game goes to the stats of its owner once it is final,
+		// a loss that can still be undone is not
+		recordStats = layoutDisclosed(game) && !game.Recorded
+		game.Recorded = game.Recorded || recordStats
+
+		history := &GameHistory{Move: &domain.Move{Row: click.Row, Col: click.Col, Kind: click.Kind, Status: game.Status, At: now}}
+		// only keep history while there are undos left, and no more than can be used
+		if game.Undos < game.UndoLimit {
+			history.Push, history.Limit = snapshot, game.UndoLimit-game.Undos
+		}
+		return history, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if !s.repo.Exists(userName) {
-		return nil, errors.New("user_not_found")
+
+	if game.Status == "won" {
+		s.submitWin(game)
 	}
 
-	game, err := s.repo.GetGame(gameName)
+	if recordStats {
+		s.submitOutcome(game)
+	}
+
+	return playerView(game), nil
+}
+
+// Undo reverts the last click or flag of the game, including a losing click.
+// Games have a budget of undos set on creation, zero disables them.
+func (s *service) Undo(gameName string, userName string) (*domain.Game, error) {
+	game, err := s.updateGame(gameName, userName, func(game *domain.Game) (*GameHistory, error) {
+		if game.Mode == "daily" {
+			return nil, errors.New("daily_game")
+		}
+
+		if game.UndoLimit == 0 {
+			return nil, errors.New("undo_disabled")
+		}
+
+		if game.Undos >= game.UndoLimit {
+			return nil, errors.New("undo_budget_exhausted")
+		}
+
+		if game.Status == "won" {
+			return nil, errors.New("game_won")
+		}
+
+		// the history only changes along with the game, so the last snapshot
+		// read here is the one of the game version being changed
+		snapshot, err := s.repo.LastSnapshot(game.Name)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			return nil, errors.New("nothing_to_undo")
+		}
+
+		game.Board = snapshot.Board
+		game.Clicks = snapshot.Clicks
+		game.Status = snapshot.Status
+		game.StartedAt = snapshot.StartedAt
+		game.Undos++
+
+		return &GameHistory{Pop: true, Move: &domain.Move{Kind: "undo", Status: game.Status, At: time.Now()}}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("Click type [%s] request at (%d, %d) for game [%s] with status [%s]", click.Kind, click.Row, click.Col, game.Name, game.Status)
+	return playerView(game), nil
+}
+
+// Resign accepts the loss of a game lost with undos left: the undos left are
+// spent, so the loss is final and the layout of the game disclosed.
+func (s *service) Resign(gameName string, userName string) (*domain.Game, error) {
+	var recordStats bool
 
-	if click.Kind != "click" && click.Kind != "flag" {
-		return nil, errors.New("bad_click_kind")
+	game, err := s.updateGame(gameName, userName, func(game *domain.Game) (*GameHistory, error) {
+		if game.Mode == "daily" {
+			return nil, errors.New("daily_game")
+		}
+
+		if game.Status != "over" {
+			return nil, errors.New("game_not_lost")
+		}
+
+		if game.Undos >= game.UndoLimit {
+			return nil, errors.New("loss_already_final")
+		}
+
+		game.Undos = game.UndoLimit
+		recordStats = !game.Recorded
+		game.Recorded = true
+		return &GameHistory{Move: &domain.Move{Kind: "resign", Status: game.Status, At: time.Now()}}, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if game.Status == "ready" {
-		// first click: set in progress and set start time
-		game.Status = "in_progress"
-		game.StartedAt = time.Now()
+	if recordStats {
+		s.submitOutcome(game)
 	}
 
-	if game.Status == "over" {
-		return nil, errors.New("game_over")
+	return playerView(game), nil
+}
+
+// Moves returns the ordered log of the moves applied to the game.
+func (s *service) Moves(gameName string, userName string) ([]*domain.Move, error) {
+	if _, err := s.playerGame(gameName, userName); err != nil {
+		return nil, err
 	}
 
-	if game.Status == "won" {
-		return nil, errors.New("game_won")
+	return s.repo.GetMoves(gameName)
+}
+
+// Replay rebuilds a finished game as it was after move n, with n from 0 (the
+// initial board) to the number of moves. A negative n replays every move.
+func (s *service) Replay(gameName string, userName string, n int) (*domain.Game, error) {
+	game, err := s.playerGame(gameName, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	// replays disclose the mine layout
+	if !layoutDisclosed(game) {
+		return nil, errors.New("game_in_progress")
+	}
+
+	moves, err := s.repo.GetMoves(gameName)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		n = len(moves)
+	}
+
+	return replayGame(game, moves, n)
+}
+
+// Verify replays the move log of the game to check the stored result can be
+// reached by playing it and was played at a human pace.
+func (s *service) Verify(gameName string, userName string) (*domain.Verification, error) {
+	game, err := s.playerGame(gameName, userName)
+	if err != nil {
+		return nil, err
 	}
 
-	if click.Kind == "click" {
-		if err := clickCell(game, click.Row, click.Col); err != nil {
+	moves, err := s.repo.GetMoves(gameName)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyGame(game, moves), nil
+}
+
+// updateGame applies a change to a game of one of its players and saves it,
+// along with the change to its history the change returns, if any. When
+// someone else saved the game in between, the change is applied again on the
+// new state, up to maxSaveAttempts times before giving up with a conflict.
+func (s *service) updateGame(gameName string, userName string, change func(game *domain.Game) (*GameHistory, error)) (*domain.Game, error) {
+	for attempt := 1; ; attempt++ {
+		game, err := s.playerGame(gameName, userName)
+		if err != nil {
 			return nil, err
 		}
-	} else if click.Kind == "flag" {
-		if err := flagCell(game, click.Row, click.Col); err != nil {
+
+		history, err := change(game)
+		if err != nil {
 			return nil, err
 		}
-	}
 
-	game.TimeSpent = time.Since(game.StartedAt)
+		_, err = s.repo.SaveGameHistory(game, history)
+		if err == ErrGameConflict && attempt < maxSaveAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return game, nil
+	}
+}
 
-	if weHaveWinner(game) {
-		game.Status = "won"
+// playerGame loads a game for one of its players: its owner or one of the
+// participants it is shared with.
+func (s *service) playerGame(gameName string, userName string) (*domain.Game, error) {
+	if !s.repo.GameExists(gameName) {
+		return nil, errors.New("game_not_found")
+	}
+	if !s.repo.UserExists(userName) {
+		return nil, errors.New("user_not_found")
 	}
 
-	if _, err := s.repo.SaveGame(game); err != nil {
+	game, err := s.repo.GetGame(gameName)
+	if err != nil {
 		return nil, err
 	}
 
+	if !isPlayer(game, userName) {
+		return nil, &apperrors.ForbiddenError{Game: gameName, Username: userName}
+	}
 	return game, nil
 }
 
-func (s *service) Board(gameName string, userName string) ([]uint8, error) {
-	if !s.repo.Exists(gameName) {
-		return nil, errors.New("game_not_found")
+func isPlayer(game *domain.Game, userName string) bool {
+	return game.Username == userName || containsString(game.Players, userName)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// submitWin verifies a won game and records it on the leaderboards. The game
+// is already saved by then, so failures are only logged.
+func (s *service) submitWin(game *domain.Game) {
+	moves, err := s.repo.GetMoves(game.Name)
+	if err != nil {
+		log.Printf("Error: Unable to read moves of game [%s]: %q", game.Name, err)
+		return
 	}
-	if !s.repo.Exists(userName) {
+	if err := recordWin(s.leaderboards, game, verifyGame(game, moves)); err != nil {
+		log.Printf("Error: Unable to record game [%s] on leaderboards: %q", game.Name, err)
+	}
+}
+
+// submitOutcome adds the result of a finished game to the stats of its owner.
+// Like submitWin it only logs failures.
+func (s *service) submitOutcome(game *domain.Game) {
+	err := s.updateUser(game.Username, func(user *domain.User) {
+		recordOutcome(&user.Stats, game)
+	})
+	if err != nil {
+		log.Printf("Error: Unable to save stats of user [%s]: %q", game.Username, err)
+	}
+}
+
+// updateUser applies a change to a user and saves it, applying it again on the
+// new state when someone else saved the user in between, like updateGame.
+func (s *service) updateUser(userName string, change func(user *domain.User)) error {
+	for attempt := 1; ; attempt++ {
+		user, err := s.repo.GetUser(userName)
+		if err != nil {
+			return err
+		}
+
+		change(user)
+
+		_, err = s.repo.SaveUser(user)
+		if err == ErrUserConflict && attempt < maxSaveAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// Stats returns the aggregate results of the games of a user.
+func (s *service) Stats(userName string) (*domain.UserStats, error) {
+	if !s.repo.UserExists(userName) {
 		return nil, errors.New("user_not_found")
 	}
 
-	game, err := s.repo.GetGame(gameName)
+	user, err := s.repo.GetUser(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Stats.BestTimes == nil {
+		user.Stats.BestTimes = map[string]time.Duration{}
+	}
+	return &user.Stats, nil
+}
+
+// Games returns a page of the games of a user, newest first. When statuses are
+// given only games in one of them are listed.
+func (s *service) Games(userName string, statuses []string, limit int, cursor string) (*domain.GamePage, error) {
+	if !s.repo.UserExists(userName) {
+		return nil, errors.New("user_not_found")
+	}
+
+	for _, status := range statuses {
+		if !gameStatuses[status] {
+			return nil, errors.New("bad_status")
+		}
+	}
+
+	offset, limit, err := pageBounds(limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// one more game than the page tells whether there is a next one
+	games, err := s.repo.GetUserGames(userName, statuses, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.GamePage{Games: []*domain.Game{}}
+	for _, game := range games {
+		page.Games = append(page.Games, summaryView(game))
+	}
+	if len(page.Games) > limit {
+		page.Games = page.Games[:limit]
+		page.NextCursor = strconv.Itoa(offset + limit)
+	}
+	return page, nil
+}
+
+// Game returns the settings and progress of a game to one of its players,
+// without its board.
+func (s *service) Game(gameName string, userName string) (*domain.Game, error) {
+	game, err := s.playerGame(gameName, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	return summaryView(game), nil
+}
+
+// DeleteGame removes a game along with its undo history and move log. Only its
+// owner can delete it. Daily games cannot be deleted, they are the only attempt
+// of the user that day.
+func (s *service) DeleteGame(gameName string, userName string) error {
+	game, err := s.playerGame(gameName, userName)
+	if err != nil {
+		return err
+	}
+	if game.Username != userName {
+		return &apperrors.ForbiddenError{Game: gameName, Username: userName}
+	}
+	if isDailyGame(gameName) {
+		return errors.New("daily_game")
+	}
+
+	if err := s.repo.DeleteGame(gameName); err != nil {
+		return err
+	}
+	if err := s.repo.ClearSnapshots(gameName); err != nil {
+		return err
+	}
+	if err := s.repo.ClearMoves(gameName); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveUserGame(game.Username, gameName)
+}
+
+func (s *service) Board(gameName string, userName string) ([]uint8, error) {
+	game, err := s.playerGame(gameName, userName)
 	if err != nil {
 		return nil, err
 	}
@@ -181,26 +580,119 @@ func (s *service) Board(gameName string, userName string) ([]uint8, error) {
 		return nil, errors.New("this game has no board")
 	}
 
-	boardToJSON := func(data [][]byte) ([]uint8, error) {
-		tmp := make([][]string, len(data), len(data[0]))
-		for i := range data {
-			row := data[i][:]
-			var fRow []string
-			for _, v := range row {
-				fRow = append(fRow, string(v))
-			}
-			tmp[i] = fRow
+	return boardToJSON(maskBoard(game))
+}
+
+// Hint returns a cell proven safe or proven to be a mine, or the lowest risk
+// cell when nothing can be proven. Every hint is counted on the game, daily
+// games get none.
+func (s *service) Hint(gameName string, userName string) (*domain.Hint, error) {
+	var hint *domain.Hint
+	_, err := s.updateGame(gameName, userName, func(game *domain.Game) (*GameHistory, error) {
+		if game.Mode == "daily" {
+			return nil, errors.New("daily_game")
 		}
-		tmpJSON, err := json.Marshal(tmp)
-		if err != nil {
-			return nil, errors.New("cannot encode to json")
+
+		if game.Status == "over" {
+			return nil, errors.New("game_over")
+		}
+
+		if game.Status == "won" {
+			return nil, errors.New("game_won")
 		}
-		return tmpJSON, nil
+
+		if hint = findHint(game); hint == nil {
+			return nil, errors.New("no_hint_available")
+		}
+
+		game.Hints++
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	jBoard, err1 := boardToJSON(game.Board)
-	if err1 != nil {
-		return nil, err1
+	return hint, nil
+}
+
+// Heatmap returns the chance of every cell being a mine. Like hints it gives
+// the player an advantage, so it is counted as a hint.
+func (s *service) Heatmap(gameName string, userName string) (*domain.Heatmap, error) {
+	var heatmap *domain.Heatmap
+	_, err := s.updateGame(gameName, userName, func(game *domain.Game) (*GameHistory, error) {
+		if game.Mode == "daily" {
+			return nil, errors.New("daily_game")
+		}
+
+		if game.Status == "over" {
+			return nil, errors.New("game_over")
+		}
+
+		if game.Status == "won" {
+			return nil, errors.New("game_won")
+		}
+
+		heatmap = mineHeatmap(game)
+		game.Hints++
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return heatmap, nil
+}
+
+// DebugBoard returns the board without masking veiled cells. It is meant for
+// admin and debugging purposes only.
+func (s *service) DebugBoard(gameName string) ([]uint8, error) {
+	if !s.repo.GameExists(gameName) {
+		return nil, errors.New("game_not_found")
+	}
+
+	game, err := s.repo.GetGame(gameName)
+	if err != nil {
+		return nil, err
+	}
+
+	if game.Board == nil {
+		return nil, errors.New("this game has no board")
+	}
+
+	return boardToJSON(game.Board)
+}
+
+// playerView returns a copy of the game safe to be sent to players. The seed
+// gives away the mine layout so it is only disclosed along with the board.
+func playerView(game *domain.Game) *domain.Game {
+	view := *game
+	view.Board = maskBoard(game)
+	if !layoutDisclosed(game) {
+		view.Seed = 0
+	}
+	return &view
+}
+
+// summaryView is the player view of a game without its board, for listings.
+func summaryView(game *domain.Game) *domain.Game {
+	view := playerView(game)
+	view.Board = nil
+	return view
+}
+
+func boardToJSON(data [][]byte) ([]uint8, error) {
+	tmp := make([][]string, len(data))
+	for i := range data {
+		row := data[i][:]
+		var fRow []string
+		for _, v := range row {
+			fRow = append(fRow, string(v))
+		}
+		tmp[i] = fRow
+	}
+	tmpJSON, err := json.Marshal(tmp)
+	if err != nil {
+		return nil, errors.New("cannot encode to json")
 	}
-	return jBoard, nil
+	return tmpJSON, nil
 }
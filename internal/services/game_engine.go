@@ -1,18 +1,38 @@
 package services
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"errors"
 	"math/rand"
-	"time"
 	"unicode"
 
 	"github.com/arllanos/minesweeper-API/internal/domain"
 )
 
-// create local random number genrator
-var randg = rand.New(rand.NewSource(time.Now().UnixNano()))
+// newSeed returns a random non-zero board seed. Seeds are disclosed when games
+// end, so they come from crypto/rand: the seeds seen cannot tell the next ones.
+func newSeed() int64 {
+	var b [8]byte
+	for {
+		if _, err := crand.Read(b[:]); err != nil {
+			panic(err)
+		}
+		if seed := int64(binary.BigEndian.Uint64(b[:]) &^ (1 << 63)); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// NW, N, NE, SE, S, SW, W, E direction vectors
+var dirVector = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {0, 1}}
 
 func generateBoard(game *domain.Game) {
+	// the seed alone determines where mines go
+	if game.Seed == 0 {
+		game.Seed = newSeed()
+	}
+
 	// initialize board
 	game.Board = make([][]byte, game.Rows)
 	for i := range game.Board {
@@ -22,21 +42,128 @@ func generateBoard(game *domain.Game) {
 		}
 	}
 
-	// plant mines randomly
+	// classic games get their mines right away, the others on the first click
+	if !deferredMines(game) {
+		plantMines(game, -1, -1)
+	}
+}
+
+// deferredMines reports whether the game plants its mines on the first click.
+func deferredMines(game *domain.Game) bool {
+	return game.FirstClick == "safe" || game.FirstClick == "opening"
+}
+
+// minesPlanted reports whether the board already holds its mines.
+func minesPlanted(game *domain.Game) bool {
+	for i := range game.Board {
+		for j := range game.Board[i] {
+			if game.Board[i][j] == 'M' || game.Board[i][j] == 'm' || game.Board[i][j] == 'X' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// plantMines plants the game mines randomly keeping the cell at (r, c) free of
+// mines and, for "opening" games, its neighbours too. New games always leave
+// room for the safe area, it only shrinks on the boards of games created before
+// that was checked. Given the same seed, dimensions and first click the layout
+// is always the same.
+func plantMines(game *domain.Game, r int, c int) {
+	plantMinesWith(rand.New(rand.NewSource(game.Seed)), game, r, c)
+}
+
+func plantMinesWith(rng *rand.Rand, game *domain.Game, r int, c int) {
+	safe := map[[2]int]bool{}
+	if r >= 0 && c >= 0 {
+		safe[[2]int{r, c}] = true
+		if game.FirstClick == "opening" {
+			for _, d := range dirVector {
+				x, y := r+d[0], c+d[1]
+				if x >= 0 && x < game.Rows && y >= 0 && y < game.Cols {
+					safe[[2]int{x, y}] = true
+				}
+			}
+		}
+	}
+	if game.Mines > game.Rows*game.Cols-len(safe) && len(safe) > 1 {
+		safe = map[[2]int]bool{{r, c}: true}
+	}
+	if game.Mines > game.Rows*game.Cols-len(safe) {
+		safe = map[[2]int]bool{}
+	}
+
+	// plant mines randomly, flagged cells keep their flag
 	i := 0
 	for i < game.Mines {
-		x := randg.Intn(game.Rows)
-		y := randg.Intn(game.Cols)
-		if game.Board[x][y] != 'M' {
+		x := rng.Intn(game.Rows)
+		y := rng.Intn(game.Cols)
+		if safe[[2]int{x, y}] {
+			continue
+		}
+		switch game.Board[x][y] {
+		case 'E':
 			game.Board[x][y] = 'M'
 			i++
+		case 'e':
+			game.Board[x][y] = 'm'
+			i++
 		}
 	}
+
+	game.ThreeBV = threeBV(game.Board)
+}
+
+// applyMove plays a click, flag or chord on the game and updates its status.
+// The first click of safe games lays out the mines.
+func applyMove(game *domain.Game, click *domain.ClickData) error {
+	if click.Kind != "click" && click.Kind != "flag" && click.Kind != "chord" {
+		return errors.New("bad_click_kind")
+	}
+
+	if game.Status == "over" {
+		return errors.New("game_over")
+	}
+
+	if game.Status == "won" {
+		return errors.New("game_won")
+	}
+
+	if click.Kind == "click" {
+		if deferredMines(game) && !minesPlanted(game) {
+			// first click on a safe game: lay out the mines around it
+			if !game.NoGuess {
+				plantMines(game, click.Row, click.Col)
+			} else if !plantSolvableMines(game, click.Row, click.Col) {
+				return errors.New("no_guess_board_unavailable")
+			}
+		}
+		if err := clickCell(game, click.Row, click.Col); err != nil {
+			return err
+		}
+	} else if click.Kind == "flag" {
+		if err := flagCell(game, click.Row, click.Col); err != nil {
+			return err
+		}
+	} else if click.Kind == "chord" {
+		if err := chordCell(game, click.Row, click.Col); err != nil {
+			return err
+		}
+	}
+
+	if game.Status == "ready" {
+		game.Status = "in_progress"
+	}
+
+	if weHaveWinner(game) {
+		game.Status = "won"
+	}
+
+	return nil
 }
 
 func clickCell(game *domain.Game, i int, j int) error {
-	// NW, N, NE, SE, S, SW, W, E direction vectors
-	dirVector := [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {0, 1}}
 	ASCII0 := 48
 
 	var solve func(board [][]byte, r int, c int)
@@ -92,6 +219,60 @@ func clickCell(game *domain.Game, i int, j int) error {
 	return nil
 }
 
+// chordCell reveals all unflagged neighbours of a revealed number once the
+// number of adjacent flags matches it. A misplaced flag makes a mine explode as
+// if it had been clicked, which stops the chord. The whole chord counts as a
+// single click.
+func chordCell(game *domain.Game, i int, j int) error {
+	if !(i >= 0 && i < game.Rows && j >= 0 && j < game.Cols) {
+		return errors.New("chorded cell out of bounds")
+	}
+
+	value := game.Board[i][j]
+	if value < '1' || value > '8' {
+		return errors.New("chorded cell is not a revealed number")
+	}
+
+	// count adjacent flags and collect veiled neighbours
+	flags := 0
+	var veiled [][2]int
+	for _, d := range dirVector {
+		x, y := i+d[0], j+d[1]
+		if !(x >= 0 && x < game.Rows && y >= 0 && y < game.Cols) {
+			continue
+		}
+		switch game.Board[x][y] {
+		case 'm', 'e':
+			flags++
+		case 'M', 'E':
+			veiled = append(veiled, [2]int{x, y})
+		}
+	}
+
+	// nothing to do unless the number is satisfied by flags
+	if flags != int(value-'0') || len(veiled) == 0 {
+		return nil
+	}
+
+	clicks := game.Clicks
+	for _, cell := range veiled {
+		// an earlier flood fill may have already revealed this cell
+		if game.Board[cell[0]][cell[1]] != 'M' && game.Board[cell[0]][cell[1]] != 'E' {
+			continue
+		}
+		if err := clickCell(game, cell[0], cell[1]); err != nil {
+			return err
+		}
+		// the first mine ends the game, the other neighbours stay veiled
+		if game.Status == "over" {
+			break
+		}
+	}
+	game.Clicks = clicks + 1
+
+	return nil
+}
+
 func flagCell(game *domain.Game, i int, j int) error {
 
 	if !(i >= 0 && i < game.Rows && j >= 0 && j < game.Cols) {
@@ -123,3 +304,156 @@ func weHaveWinner(game *domain.Game) bool {
 
 	return true
 }
+
+// maskBoard returns a copy of the board as a player is allowed to see it. While
+// the game is being played veiled mines are indistinguishable from veiled empty
+// cells, once its layout is disclosed the whole board is.
+func maskBoard(game *domain.Game) [][]byte {
+	if game.Board == nil {
+		return nil
+	}
+
+	board := copyBoard(game.Board)
+	for i := range board {
+		if layoutDisclosed(game) {
+			continue
+		}
+		for j := range board[i] {
+			switch board[i][j] {
+			case 'M':
+				board[i][j] = 'E'
+			case 'm':
+				board[i][j] = 'e'
+			}
+		}
+	}
+
+	return board
+}
+
+// layoutDisclosed reports whether players may see where the mines are: the
+// game is won, or lost for good. A lost game with undos left still has the
+// losing click pushed on its undo history, showing the mines would let the
+// player undo it and clear the board.
+func layoutDisclosed(game *domain.Game) bool {
+	return game.Status == "won" || (game.Status == "over" && game.Undos >= game.UndoLimit)
+}
+
+func copyBoard(board [][]byte) [][]byte {
+	if board == nil {
+		return nil
+	}
+	result := make([][]byte, len(board))
+	for i := range board {
+		result[i] = make([]byte, len(board[i]))
+		copy(result[i], board[i])
+	}
+	return result
+}
+
+// startCell returns the mine free cell with no adjacent mines closest to the
+// center of the board, or the closest mine free cell if there is none.
+func startCell(game *domain.Game) (int, int) {
+	bestRow, bestCol, bestDist, bestBlank := -1, -1, 0, false
+	for i := range game.Board {
+		for j := range game.Board[i] {
+			if game.Board[i][j] == 'M' || game.Board[i][j] == 'm' {
+				continue
+			}
+			blank := true
+			for _, d := range dirVector {
+				x, y := i+d[0], j+d[1]
+				if x >= 0 && x < game.Rows && y >= 0 && y < game.Cols && (game.Board[x][y] == 'M' || game.Board[x][y] == 'm') {
+					blank = false
+					break
+				}
+			}
+			dr, dc := 2*i-(game.Rows-1), 2*j-(game.Cols-1)
+			dist := dr*dr + dc*dc
+			if bestRow < 0 || (blank && !bestBlank) || (blank == bestBlank && dist < bestDist) {
+				bestRow, bestCol, bestDist, bestBlank = i, j, dist, blank
+			}
+		}
+	}
+	return bestRow, bestCol
+}
+
+// threeBV returns the Bechtel's Board Benchmark Value of a board: the minimum
+// number of clicks needed to clear it without flags. Every opening (a group of
+// connected cells with no adjacent mines, along with its border) takes one
+// click, every other mine free cell takes one click of its own.
+func threeBV(board [][]byte) int {
+	rows := len(board)
+	if rows == 0 {
+		return 0
+	}
+	cols := len(board[0])
+
+	isMine := func(r int, c int) bool {
+		return board[r][c] == 'M' || board[r][c] == 'm' || board[r][c] == 'X'
+	}
+	isZero := func(r int, c int) bool {
+		if isMine(r, c) {
+			return false
+		}
+		for _, d := range dirVector {
+			x, y := r+d[0], c+d[1]
+			if x >= 0 && x < rows && y >= 0 && y < cols && isMine(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+
+	marked := make([][]bool, rows)
+	for i := range marked {
+		marked[i] = make([]bool, cols)
+	}
+
+	var flood func(r int, c int)
+	flood = func(r int, c int) {
+		marked[r][c] = true
+		if !isZero(r, c) {
+			return
+		}
+		for _, d := range dirVector {
+			x, y := r+d[0], c+d[1]
+			if x >= 0 && x < rows && y >= 0 && y < cols && !marked[x][y] {
+				flood(x, y)
+			}
+		}
+	}
+
+	count := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if !marked[r][c] && isZero(r, c) {
+				flood(r, c)
+				count++
+			}
+		}
+	}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if !marked[r][c] && !isMine(r, c) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// scoreGame computes the competitive metrics of a won game: 3BV per second
+// and click efficiency (3BV over clicks).
+func scoreGame(game *domain.Game) {
+	if game.Status != "won" {
+		return
+	}
+	if seconds := game.TimeSpent.Seconds(); seconds > 0 {
+		game.ThreeBVPerSecond = float64(game.ThreeBV) / seconds
+	}
+	if game.Clicks > 0 {
+		game.Efficiency = float64(game.ThreeBV) / float64(game.Clicks)
+	}
+}
@@ -1,12 +1,35 @@
 package services
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/arllanos/minesweeper-API/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestNewSeed(t *testing.T) {
+	seeds := map[int64]bool{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seed := newSeed()
+			mu.Lock()
+			seeds[seed] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seeds, 100)
+	for seed := range seeds {
+		assert.Positive(t, seed)
+	}
+}
+
 func TestClickCellOutOfBounds(t *testing.T) {
 	var game domain.Game
 	game.Name = "TestGame1"
@@ -32,3 +55,166 @@ func TestClickCellWithinBounds(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func TestDeferredMinesFirstClickIsSafe(t *testing.T) {
+	for _, mode := range []string{"safe", "opening"} {
+		var game domain.Game
+		game.Rows = 5
+		game.Cols = 5
+		game.Mines = 16
+		game.FirstClick = mode
+		generateBoard(&game)
+
+		assert.False(t, minesPlanted(&game))
+
+		plantMines(&game, 2, 2)
+		err := clickCell(&game, 2, 2)
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, "over", game.Status)
+		if mode == "opening" {
+			assert.Equal(t, byte('B'), game.Board[2][2])
+		}
+	}
+}
+
+func TestMaskBoardHidesMinesUntilGameEnds(t *testing.T) {
+	var game domain.Game
+	game.Status = "in_progress"
+	game.Board = [][]byte{
+		{'M', 'm'},
+		{'1', 'E'},
+	}
+
+	assert.Equal(t, [][]byte{{'E', 'e'}, {'1', 'E'}}, maskBoard(&game))
+	assert.Equal(t, byte('M'), game.Board[0][0])
+
+	game.Status = "over"
+	assert.Equal(t, game.Board, maskBoard(&game))
+}
+
+func TestChordCellRevealsNeighbours(t *testing.T) {
+	var game domain.Game
+	game.Rows = 3
+	game.Cols = 3
+	game.Status = "in_progress"
+	game.Board = [][]byte{
+		{'m', 'E', 'E'},
+		{'E', '1', 'E'},
+		{'E', 'E', 'E'},
+	}
+
+	err := chordCell(&game, 1, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, game.Clicks)
+	assert.Equal(t, [][]byte{{'m', '1', 'B'}, {'1', '1', 'B'}, {'B', 'B', 'B'}}, game.Board)
+}
+
+func TestChordCellWrongFlagEndsGame(t *testing.T) {
+	var game domain.Game
+	game.Rows = 2
+	game.Cols = 2
+	game.Status = "in_progress"
+	game.Board = [][]byte{
+		{'e', 'M'},
+		{'1', 'E'},
+	}
+
+	err := chordCell(&game, 1, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+	assert.Equal(t, byte('X'), game.Board[0][1])
+}
+
+func TestChordCellStopsAtFirstMine(t *testing.T) {
+	var game domain.Game
+	game.Rows = 3
+	game.Cols = 3
+	game.Status = "in_progress"
+	game.Board = [][]byte{
+		{'M', 'E', 'E'},
+		{'E', '2', 'E'},
+		{'e', 'e', 'M'},
+	}
+
+	err := chordCell(&game, 1, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+	assert.Equal(t, 1, game.Clicks)
+	assert.Equal(t, [][]byte{{'X', 'E', 'E'}, {'E', '2', 'E'}, {'e', 'e', 'M'}}, game.Board)
+}
+
+func TestPlantSolvableMinesIsSolvable(t *testing.T) {
+	var game domain.Game
+	game.Rows = 9
+	game.Cols = 9
+	game.Mines = 10
+	game.FirstClick = "opening"
+	game.NoGuess = true
+	generateBoard(&game)
+
+	assert.True(t, plantSolvableMines(&game, 4, 4))
+	assert.True(t, minesPlanted(&game))
+	assert.True(t, solvable(&game, 4, 4))
+}
+
+func TestSameSeedSameBoard(t *testing.T) {
+	for _, mode := range []string{"classic", "opening"} {
+		boards := make([][][]byte, 2)
+		for i := range boards {
+			var game domain.Game
+			game.Rows = 16
+			game.Cols = 16
+			game.Mines = 40
+			game.FirstClick = mode
+			game.Seed = 42
+			generateBoard(&game)
+			if deferredMines(&game) {
+				plantMines(&game, 8, 8)
+			}
+			boards[i] = game.Board
+		}
+
+		assert.Equal(t, boards[0], boards[1])
+	}
+}
+
+func TestDailyGameIsSharedAndStartCellIsBlank(t *testing.T) {
+	key := []byte("secret")
+	alice := dailyGame(key, "2026-10-18", "alice")
+	bob := dailyGame(key, "2026-10-18", "bob")
+	other := dailyGame(key, "2026-10-19", "alice")
+
+	assert.Equal(t, alice.Board, bob.Board)
+	assert.NotEqual(t, alice.Board, other.Board)
+	assert.NotEqual(t, alice.Seed, dailyGame([]byte("other"), "2026-10-18", "alice").Seed)
+
+	r, c := startCell(alice)
+	err := clickCell(alice, r, c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, byte('B'), alice.Board[r][c])
+}
+
+func TestThreeBV(t *testing.T) {
+	board := [][]byte{
+		{'M', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'M'},
+	}
+
+	// a single opening reaches every mine free cell
+	assert.Equal(t, 1, threeBV(board))
+
+	board = [][]byte{
+		{'E', 'M', 'E'},
+		{'M', 'E', 'M'},
+		{'E', 'M', 'E'},
+	}
+	// no openings, every mine free cell takes a click
+	assert.Equal(t, 5, threeBV(board))
+}
@@ -1,12 +1,63 @@
 package services
 
-import "github.com/arllanos/minesweeper-API/internal/domain"
+import (
+	"errors"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+var (
+	// ErrGameConflict and ErrUserConflict are returned by SaveGame and
+	// SaveUser when the record was saved by someone else since it was read.
+	ErrGameConflict = errors.New("game_conflict")
+	ErrUserConflict = errors.New("user_conflict")
+	// ErrGameNotFound and ErrUserNotFound are returned when reading a game or a
+	// user that is not stored.
+	ErrGameNotFound = errors.New("game not found")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// GameHistory is a change to the undo history and the move log of a game,
+// saved along with the game by SaveGameHistory. The changes are applied in the
+// order of the fields.
+type GameHistory struct {
+	// Reset empties the undo history and the move log, for a restarted game
+	Reset bool
+	// Pop drops the last snapshot of the undo history
+	Pop bool
+	// Push adds a snapshot to the undo history, which then keeps its last
+	// Limit snapshots only (Limit >= 1)
+	Push  *domain.Snapshot
+	Limit int
+	// Move is appended to the move log
+	Move *domain.Move
+}
 
 type GameRepository interface {
+	// SaveGame only saves a game still at the version it was read at (0 for a
+	// new game) and moves it to the next version
 	SaveGame(game *domain.Game) (*domain.Game, error)
-	SaveUser(game *domain.User) (*domain.User, error)
-	GetGame(key string) (*domain.Game, error)
-	GetUser(key string) (*domain.User, error)
-	Exists(key string) bool
-	Delete(key string) error
+	// SaveGameHistory saves a game like SaveGame and changes its history in
+	// the same transaction: either both are saved or none is, so the history
+	// follows the order of the game versions
+	SaveGameHistory(game *domain.Game, history *GameHistory) (*domain.Game, error)
+	// SaveUser follows the same rule with the version of the user
+	SaveUser(user *domain.User) (*domain.User, error)
+	GetGame(gameName string) (*domain.Game, error)
+	GetUser(userName string) (*domain.User, error)
+	UserExists(userName string) bool
+	GameExists(gameName string) bool
+	// DeleteGame removes a game along with its board
+	DeleteGame(gameName string) error
+	// page of the games of a user without their boards, newest first. When
+	// statuses are given only games in one of them are listed. Saving a game
+	// indexes it under its owner
+	GetUserGames(userName string, statuses []string, offset int, limit int) ([]*domain.Game, error)
+	RemoveUserGame(userName string, gameName string) error
+	// undo history of a game, LastSnapshot returns nil when it is empty
+	LastSnapshot(gameName string) (*domain.Snapshot, error)
+	ClearSnapshots(gameName string) error
+	// ordered log of the moves applied to a game
+	GetMoves(gameName string) ([]*domain.Move, error)
+	ClearMoves(gameName string) error
 }
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPlayer(t *testing.T) {
+	game := &domain.Game{Username: "owner", Players: []string{"friend"}}
+
+	assert.True(t, isPlayer(game, "owner"))
+	assert.True(t, isPlayer(game, "friend"))
+	assert.False(t, isPlayer(game, "stranger"))
+}
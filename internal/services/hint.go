@@ -0,0 +1,114 @@
+package services
+
+import (
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+// findHint looks for a cell the player can act on with certainty, preferring
+// safe cells over mines that are not flagged yet. When logic cannot prove
+// anything it falls back to the veiled cell with the lowest risk.
+func findHint(game *domain.Game) *domain.Hint {
+	board := maskBoard(game)
+
+	// mines are laid out on the first click, any cell is safe until then. Flags
+	// may come before it, so the first click goes to a cell that is not flagged
+	if deferredMines(game) && !minesPlanted(game) {
+		if game.Board[game.Rows/2][game.Cols/2] == 'E' {
+			return &domain.Hint{Row: game.Rows / 2, Col: game.Cols / 2, Rule: "first_click"}
+		}
+		for i := range game.Board {
+			for j := range game.Board[i] {
+				if game.Board[i][j] == 'E' {
+					return &domain.Hint{Row: i, Col: j, Rule: "first_click"}
+				}
+			}
+		}
+		return nil
+	}
+
+	s := newSolver(board, game.Mines)
+	for {
+		deductions := s.deduce()
+		if len(deductions) == 0 {
+			break
+		}
+		for _, d := range deductions {
+			if !d.mine {
+				return toHint(d, 0)
+			}
+		}
+		for _, d := range deductions {
+			if board[d.row][d.col] != 'e' {
+				return toHint(d, 1)
+			}
+		}
+		// every proven mine is already flagged, build on them
+		for _, d := range deductions {
+			s.known[d.row][d.col] = cellMine
+		}
+	}
+
+	r, c, risk := s.lowestRisk()
+	if r < 0 {
+		return nil
+	}
+	return &domain.Hint{Row: r, Col: c, Probability: risk, Rule: "lowest_risk"}
+}
+
+// lowestRisk returns the undetermined cell with the lowest chance of being a
+// mine, using exact probabilities when the frontier can be enumerated.
+func (s *solver) lowestRisk() (int, int, float64) {
+	global := s.globalConstraint()
+	if len(global.cells) == 0 {
+		return -1, -1, 0
+	}
+
+	probs, exact := s.probabilities()
+	if !exact {
+		probs = s.estimate()
+	}
+
+	best, bestRisk := global.cells[0], 2.0
+	for _, cell := range global.cells {
+		if p := probs[cell[0]][cell[1]]; p < bestRisk {
+			best, bestRisk = cell, p
+		}
+	}
+	return best[0], best[1], bestRisk
+}
+
+// mineHeatmap returns the chance of every cell being a mine as a player sees
+// the game. Revealed cells are never mines.
+func mineHeatmap(game *domain.Game) *domain.Heatmap {
+	heatmap := &domain.Heatmap{Rows: game.Rows, Cols: game.Cols, Exact: true}
+
+	// mines are laid out on the first click, until then every cell is as likely
+	// to get one. The safe first cell is not known yet, so this is an estimate
+	if deferredMines(game) && !minesPlanted(game) {
+		prior := float64(game.Mines) / float64(game.Rows*game.Cols)
+		heatmap.Exact = false
+		heatmap.Probabilities = make([][]float64, game.Rows)
+		for i := range heatmap.Probabilities {
+			heatmap.Probabilities[i] = make([]float64, game.Cols)
+			for j := range heatmap.Probabilities[i] {
+				heatmap.Probabilities[i][j] = prior
+			}
+		}
+		return heatmap
+	}
+
+	s := newSolver(maskBoard(game), game.Mines)
+	heatmap.Probabilities, heatmap.Exact = s.probabilities()
+	if !heatmap.Exact {
+		heatmap.Probabilities = s.estimate()
+	}
+	return heatmap
+}
+
+func toHint(d deduction, probability float64) *domain.Hint {
+	hint := &domain.Hint{Row: d.row, Col: d.col, Mine: d.mine, Probability: probability, Rule: d.rule}
+	for _, cons := range d.constraints {
+		hint.Constraints = append(hint.Constraints, domain.Constraint{Row: cons.row, Col: cons.col, Cells: cons.cells, Mines: cons.mines})
+	}
+	return hint
+}
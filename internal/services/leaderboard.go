@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+const globalLeaderboard = "global"
+
+var customLeaderboard = regexp.MustCompile(`^custom-\d+x\d+-\d+$`)
+
+type LeaderboardService interface {
+	Leaderboard(name string, limit int, cursor string) (*domain.LeaderboardPage, error)
+}
+
+type leaderboardService struct {
+	repo LeaderboardRepository
+}
+
+func NewLeaderboardService(db LeaderboardRepository) LeaderboardService {
+	return &leaderboardService{repo: db}
+}
+
+// Leaderboard returns a page of a leaderboard: a difficulty preset, a custom
+// configuration as custom-{rows}x{cols}-{mines}, or global. The cursor
+// returned with a page gives the next one.
+func (s *leaderboardService) Leaderboard(name string, limit int, cursor string) (*domain.LeaderboardPage, error) {
+	if _, ok := presets[name]; !ok && name != globalLeaderboard && !customLeaderboard.MatchString(name) {
+		return nil, errors.New("leaderboard_not_found")
+	}
+
+	offset, limit, err := pageBounds(limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch one more entry to know whether there is a next page
+	entries, err := s.repo.GetEntries(name, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.LeaderboardPage{Leaderboard: name, Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = strconv.Itoa(offset + limit)
+	}
+	for i, entry := range page.Entries {
+		entry.Rank = offset + i + 1
+	}
+
+	return page, nil
+}
+
+// leaderboardName returns the leaderboard of the board configuration of a game.
+func leaderboardName(game *domain.Game) string {
+	if _, ok := presets[game.Difficulty]; ok {
+		return game.Difficulty
+	}
+	return fmt.Sprintf("custom-%dx%d-%d", game.Rows, game.Cols, game.Mines)
+}
+
+// recordWin ranks a won game on the leaderboard of its configuration, by time
+// spent with ties broken by clicks, and on the global leaderboard, by 3BV/s
+// since times of different configurations cannot be compared. Assisted games,
+// shared games, games on a seed chosen by the player, daily games (they have
+// their own leaderboard) and games that do not pass verification are left out.
+func recordWin(repo LeaderboardRepository, game *domain.Game, verification *domain.Verification) error {
+	if game.Status != "won" || game.Mode == "daily" || game.Hints > 0 || game.Undos > 0 || len(game.Players) > 0 || game.CustomSeed {
+		return nil
+	}
+	if !verification.Passed {
+		log.Printf("Game [%s] left out of leaderboards: %v", game.Name, verification.Problems)
+		return nil
+	}
+
+	entry := &domain.LeaderboardEntry{
+		Username:         game.Username,
+		Game:             game.Name,
+		Difficulty:       game.Difficulty,
+		Rows:             game.Rows,
+		Cols:             game.Cols,
+		Mines:            game.Mines,
+		Clicks:           game.Clicks,
+		TimeSpent:        game.TimeSpent,
+		ThreeBV:          game.ThreeBV,
+		ThreeBVPerSecond: game.ThreeBVPerSecond,
+		Efficiency:       game.Efficiency,
+		FinishedAt:       game.StartedAt.Add(game.TimeSpent),
+	}
+
+	// milliseconds first, clicks as the fraction to break ties
+	score := float64(game.TimeSpent/time.Millisecond) + float64(game.Clicks)/1e6
+	if err := repo.AddEntry(leaderboardName(game), entry, score); err != nil {
+		return err
+	}
+
+	return repo.AddEntry(globalLeaderboard, entry, -game.ThreeBVPerSecond)
+}
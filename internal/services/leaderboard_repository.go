@@ -0,0 +1,11 @@
+package services
+
+import "github.com/arllanos/minesweeper-API/internal/domain"
+
+// LeaderboardRepository keeps the entries of each leaderboard ordered by
+// score, lowest first. GetEntries returns at most limit entries starting at
+// offset in that order.
+type LeaderboardRepository interface {
+	AddEntry(leaderboard string, entry *domain.LeaderboardEntry, score float64) error
+	GetEntries(leaderboard string, offset int, limit int) ([]*domain.LeaderboardEntry, error)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type scoredEntry struct {
+	entry *domain.LeaderboardEntry
+	score float64
+}
+
+type fakeLeaderboards map[string][]scoredEntry
+
+func (f fakeLeaderboards) AddEntry(leaderboard string, entry *domain.LeaderboardEntry, score float64) error {
+	f[leaderboard] = append(f[leaderboard], scoredEntry{entry: entry, score: score})
+	sort.SliceStable(f[leaderboard], func(i, j int) bool { return f[leaderboard][i].score < f[leaderboard][j].score })
+	return nil
+}
+
+func (f fakeLeaderboards) GetEntries(leaderboard string, offset int, limit int) ([]*domain.LeaderboardEntry, error) {
+	var entries []*domain.LeaderboardEntry
+	for i := offset; i < len(f[leaderboard]) && i < offset+limit; i++ {
+		entries = append(entries, f[leaderboard][i].entry)
+	}
+	return entries, nil
+}
+
+func TestRecordWinRanksByTimeThenClicks(t *testing.T) {
+	repo := fakeLeaderboards{}
+	passed := &domain.Verification{Valid: true, Passed: true}
+	win := func(name string, seconds int, clicks int) *domain.Game {
+		return &domain.Game{Name: name, Username: name, Difficulty: "beginner", Status: "won", Clicks: clicks, TimeSpent: time.Duration(seconds) * time.Second}
+	}
+
+	assert.Nil(t, recordWin(repo, win("slow", 30, 10), passed))
+	assert.Nil(t, recordWin(repo, win("fast", 10, 20), passed))
+	assert.Nil(t, recordWin(repo, win("tidy", 10, 15), passed))
+	assisted := win("assisted", 1, 1)
+	assisted.Hints = 1
+	assert.Nil(t, recordWin(repo, assisted, passed))
+	shared := win("shared", 1, 1)
+	shared.Players = []string{"friend"}
+	assert.Nil(t, recordWin(repo, shared, passed))
+	rehearsed := win("rehearsed", 1, 1)
+	rehearsed.CustomSeed = true
+	assert.Nil(t, recordWin(repo, rehearsed, passed))
+	assert.Nil(t, recordWin(repo, win("forged", 1, 1), &domain.Verification{}))
+
+	service := NewLeaderboardService(repo)
+	page, err := service.Leaderboard("beginner", 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "tidy", page.Entries[0].Game)
+	assert.Equal(t, "fast", page.Entries[1].Game)
+	assert.Equal(t, 2, page.Entries[1].Rank)
+	assert.Equal(t, "2", page.NextCursor)
+
+	page, err = service.Leaderboard("beginner", 2, page.NextCursor)
+	assert.Nil(t, err)
+	assert.Len(t, page.Entries, 1)
+	assert.Equal(t, 3, page.Entries[0].Rank)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = service.Leaderboard("impossible", 2, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// pageBounds validates the limit and cursor of a paginated request. A zero
+// limit takes the default one, the cursor is the offset of the page.
+func pageBounds(limit int, cursor string) (int, int, error) {
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	if limit < 0 || limit > maxPageLimit {
+		return 0, 0, errors.New("bad_limit")
+	}
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return 0, 0, errors.New("bad_cursor")
+		}
+	}
+
+	return offset, limit, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageBounds(t *testing.T) {
+	offset, limit, err := pageBounds(0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, offset)
+	assert.Equal(t, defaultPageLimit, limit)
+
+	offset, limit, err = pageBounds(5, "10")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, offset)
+	assert.Equal(t, 5, limit)
+
+	_, _, err = pageBounds(maxPageLimit+1, "")
+	assert.EqualError(t, err, "bad_limit")
+
+	_, _, err = pageBounds(5, "-1")
+	assert.EqualError(t, err, "bad_cursor")
+}
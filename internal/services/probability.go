@@ -0,0 +1,307 @@
+package services
+
+import (
+	"math"
+)
+
+// maxEnumerationSteps bounds the backtracking done to enumerate the frontier
+// layouts. Past it probabilities are estimated instead.
+const maxEnumerationSteps = 2000000
+
+// component is a group of frontier cells linked by the constraints they share,
+// along with how many of its layouts hold k mines (layouts[k]) and how many of
+// those have a mine in each cell (mineLayouts[cell][k]).
+type component struct {
+	cells       [][2]int
+	constraints []constraint
+	layouts     []float64
+	mineLayouts [][]float64
+}
+
+// probabilities computes the exact chance of every cell being a mine given the
+// revealed numbers and the mines left. Frontier cells (next to a revealed
+// number) are solved by enumerating every layout consistent with the numbers,
+// interior cells share the mines the frontier layouts leave out evenly. It
+// reports false when the frontier is too large to enumerate.
+func (s *solver) probabilities() ([][]float64, bool) {
+	result := make([][]float64, s.rows)
+	for i := range result {
+		result[i] = make([]float64, s.cols)
+	}
+
+	global := s.globalConstraint()
+	if len(global.cells) == 0 {
+		return result, true
+	}
+
+	components := s.components()
+	frontier := map[[2]int]bool{}
+	steps := 0
+	for _, comp := range components {
+		if !comp.enumerate(global.mines, &steps) {
+			return nil, false
+		}
+		for _, cell := range comp.cells {
+			frontier[cell] = true
+		}
+	}
+	interior := len(global.cells) - len(frontier)
+
+	// weight of leaving m mines to the interior: C(interior, m), scaled to avoid overflow
+	logWeights := make([]float64, global.mines+1)
+	maxLog := math.Inf(-1)
+	for m := range logWeights {
+		logWeights[m] = logCombinations(interior, m)
+		maxLog = math.Max(maxLog, logWeights[m])
+	}
+	weight := func(m int) float64 {
+		if m < 0 || m > global.mines || math.IsInf(logWeights[m], -1) {
+			return 0
+		}
+		return math.Exp(logWeights[m] - maxLog)
+	}
+
+	all := convolve(components, -1)
+	total, interiorMines := 0.0, 0.0
+	for k, n := range all {
+		w := n * weight(global.mines-k)
+		total += w
+		interiorMines += w * float64(global.mines-k)
+	}
+	if total == 0 {
+		// the revealed numbers contradict the mine count
+		return nil, false
+	}
+
+	for i, comp := range components {
+		others := convolve(components, i)
+		for c, cell := range comp.cells {
+			p := 0.0
+			for k, n := range comp.mineLayouts[c] {
+				if n == 0 {
+					continue
+				}
+				for j, o := range others {
+					p += n * o * weight(global.mines-k-j)
+				}
+			}
+			result[cell[0]][cell[1]] = p / total
+		}
+	}
+
+	if interior > 0 {
+		p := interiorMines / total / float64(interior)
+		for _, cell := range global.cells {
+			if !frontier[cell] {
+				result[cell[0]][cell[1]] = p
+			}
+		}
+	}
+
+	for r := 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c++ {
+			if s.known[r][c] == cellMine {
+				result[r][c] = 1
+			}
+		}
+	}
+
+	return result, true
+}
+
+// estimate approximates the chance of every cell being a mine. Cells next to
+// revealed numbers take the highest density among their constraints, the other
+// ones the density of the mines left.
+func (s *solver) estimate() [][]float64 {
+	result := make([][]float64, s.rows)
+	for i := range result {
+		result[i] = make([]float64, s.cols)
+	}
+
+	global := s.globalConstraint()
+	if len(global.cells) == 0 {
+		return result
+	}
+	density := float64(global.mines) / float64(len(global.cells))
+	for _, cell := range global.cells {
+		result[cell[0]][cell[1]] = density
+	}
+
+	bounded := map[[2]int]bool{}
+	for _, cons := range s.constraints() {
+		p := float64(cons.mines) / float64(len(cons.cells))
+		for _, cell := range cons.cells {
+			if !bounded[cell] || p > result[cell[0]][cell[1]] {
+				result[cell[0]][cell[1]] = p
+			}
+			bounded[cell] = true
+		}
+	}
+
+	for r := 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c++ {
+			if s.known[r][c] == cellMine {
+				result[r][c] = 1
+			}
+		}
+	}
+
+	return result
+}
+
+// components splits the frontier in groups of cells that do not share any
+// constraint, so each one can be enumerated on its own.
+func (s *solver) components() []*component {
+	constraints := s.constraints()
+
+	byCell := map[[2]int][]int{}
+	for i, cons := range constraints {
+		for _, cell := range cons.cells {
+			byCell[cell] = append(byCell[cell], i)
+		}
+	}
+
+	var result []*component
+	visited := make([]bool, len(constraints))
+	for i := range constraints {
+		if visited[i] {
+			continue
+		}
+		comp := &component{}
+		inComp := map[[2]int]bool{}
+		queue := []int{i}
+		visited[i] = true
+		for len(queue) > 0 {
+			cons := constraints[queue[0]]
+			queue = queue[1:]
+			comp.constraints = append(comp.constraints, cons)
+			for _, cell := range cons.cells {
+				if !inComp[cell] {
+					inComp[cell] = true
+					comp.cells = append(comp.cells, cell)
+				}
+				for _, j := range byCell[cell] {
+					if !visited[j] {
+						visited[j] = true
+						queue = append(queue, j)
+					}
+				}
+			}
+		}
+		result = append(result, comp)
+	}
+
+	return result
+}
+
+// enumerate counts every mine layout of the component that satisfies its
+// constraints with at most maxMines mines. It gives up once steps goes past
+// maxEnumerationSteps.
+func (comp *component) enumerate(maxMines int, steps *int) bool {
+	index := map[[2]int]int{}
+	for i, cell := range comp.cells {
+		index[cell] = i
+	}
+	cellConstraints := make([][]int, len(comp.cells))
+	need := make([]int, len(comp.constraints))
+	left := make([]int, len(comp.constraints))
+	for i, cons := range comp.constraints {
+		need[i] = cons.mines
+		left[i] = len(cons.cells)
+		for _, cell := range cons.cells {
+			cellConstraints[index[cell]] = append(cellConstraints[index[cell]], i)
+		}
+	}
+
+	comp.layouts = make([]float64, len(comp.cells)+1)
+	comp.mineLayouts = make([][]float64, len(comp.cells))
+	for i := range comp.mineLayouts {
+		comp.mineLayouts[i] = make([]float64, len(comp.cells)+1)
+	}
+
+	mine := make([]bool, len(comp.cells))
+	var solve func(i int, mines int) bool
+	solve = func(i int, mines int) bool {
+		*steps++
+		if *steps > maxEnumerationSteps {
+			return false
+		}
+		if i == len(comp.cells) {
+			comp.layouts[mines]++
+			for j := range mine {
+				if mine[j] {
+					comp.mineLayouts[j][mines]++
+				}
+			}
+			return true
+		}
+
+		for _, value := range []bool{false, true} {
+			if value && mines == maxMines {
+				continue
+			}
+			feasible := true
+			for _, ci := range cellConstraints[i] {
+				left[ci]--
+				if value {
+					need[ci]--
+				}
+				if need[ci] < 0 || need[ci] > left[ci] {
+					feasible = false
+				}
+			}
+			mine[i] = value
+			next := mines
+			if value {
+				next++
+			}
+			ok := !feasible || solve(i+1, next)
+			for _, ci := range cellConstraints[i] {
+				left[ci]++
+				if value {
+					need[ci]++
+				}
+			}
+			mine[i] = false
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	return solve(0, 0)
+}
+
+// convolve combines the layout counts of all components but skip into the
+// number of layouts for each total of mines.
+func convolve(components []*component, skip int) []float64 {
+	result := []float64{1}
+	for i, comp := range components {
+		if i == skip {
+			continue
+		}
+		next := make([]float64, len(result)+len(comp.layouts)-1)
+		for a, x := range result {
+			if x == 0 {
+				continue
+			}
+			for b, y := range comp.layouts {
+				next[a+b] += x * y
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// logCombinations returns the natural logarithm of n choose k.
+func logCombinations(n int, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+const (
+	// moves closer than minHumanGap are too fast for a human...
+	minHumanGap = 10 * time.Millisecond
+	// ...which can happen by accident, but not this many times in a game
+	maxFastMoves = 10
+)
+
+// replayer plays the moves of a game log on a new board generated from the
+// game seed. Undo moves revert the move before them like they did when the
+// game was played, resign moves spend the undos left.
+type replayer struct {
+	game    *domain.Game
+	history []*domain.Snapshot
+}
+
+func newReplayer(game *domain.Game) *replayer {
+	replay := &domain.Game{
+		Name:       game.Name,
+		Username:   game.Username,
+		Rows:       game.Rows,
+		Cols:       game.Cols,
+		Mines:      game.Mines,
+		Difficulty: game.Difficulty,
+		FirstClick: game.FirstClick,
+		NoGuess:    game.NoGuess,
+		Seed:       game.Seed,
+		Mode:       game.Mode,
+		UndoLimit:  game.UndoLimit,
+		CreatedAt:  game.CreatedAt,
+		Status:     "ready",
+	}
+	generateBoard(replay)
+	return &replayer{game: replay}
+}
+
+func (r *replayer) step(move *domain.Move) error {
+	replay := r.game
+	if move.Kind == "undo" {
+		if len(r.history) == 0 {
+			return errors.New("bad_move_log")
+		}
+		last := r.history[len(r.history)-1]
+		r.history = r.history[:len(r.history)-1]
+		replay.Board, replay.Clicks, replay.Status, replay.StartedAt = last.Board, last.Clicks, last.Status, last.StartedAt
+		replay.Undos++
+		return nil
+	}
+	if move.Kind == "resign" {
+		if replay.Status != "over" {
+			return errors.New("bad_move_log")
+		}
+		replay.Undos = replay.UndoLimit
+		return nil
+	}
+
+	r.history = append(r.history, &domain.Snapshot{
+		Board:     copyBoard(replay.Board),
+		Clicks:    replay.Clicks,
+		Status:    replay.Status,
+		StartedAt: replay.StartedAt,
+	})
+	if replay.Status == "ready" {
+		replay.StartedAt = move.At
+	}
+	if err := applyMove(replay, &domain.ClickData{Row: move.Row, Col: move.Col, Kind: move.Kind}); err != nil {
+		return err
+	}
+	replay.TimeSpent = move.At.Sub(replay.StartedAt)
+	scoreGame(replay)
+	return nil
+}
+
+// replayGame rebuilds the game as it was after its first n moves.
+func replayGame(game *domain.Game, moves []*domain.Move, n int) (*domain.Game, error) {
+	if n < 0 || n > len(moves) {
+		return nil, errors.New("bad_move_number")
+	}
+
+	r := newReplayer(game)
+	for _, move := range moves[:n] {
+		if err := r.step(move); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.game, nil
+}
+
+// verifyGame replays the whole move log of a game and checks that it leads to
+// the stored result: the status after every move, and the final board (hence
+// the initial mine layout given by the seed), clicks, undos and time spent. It
+// also flags games played at a pace no human can keep.
+func verifyGame(game *domain.Game, moves []*domain.Move) *domain.Verification {
+	result := &domain.Verification{Game: game.Name, Moves: len(moves)}
+	fail := func(format string, args ...interface{}) {
+		result.Problems = append(result.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if len(moves) == 0 && game.Status != "ready" {
+		fail("game has no move log")
+	}
+
+	r := newReplayer(game)
+	fastMoves := 0
+	for i, move := range moves {
+		if i > 0 {
+			gap := move.At.Sub(moves[i-1].At)
+			if gap < 0 {
+				fail("move %d happened before move %d", i+1, i)
+			} else if gap < minHumanGap {
+				fastMoves++
+			}
+		}
+		if err := r.step(move); err != nil {
+			fail("move %d cannot be applied: %s", i+1, err.Error())
+			break
+		}
+		if r.game.Status != move.Status {
+			fail("move %d leads to status %s, %s recorded", i+1, r.game.Status, move.Status)
+		}
+	}
+
+	replay := r.game
+	if len(result.Problems) == 0 {
+		if replay.Status != game.Status {
+			fail("replay ends with status %s, %s stored", replay.Status, game.Status)
+		}
+		if replay.Clicks != game.Clicks {
+			fail("replay takes %d clicks, %d stored", replay.Clicks, game.Clicks)
+		}
+		if replay.ThreeBV != game.ThreeBV {
+			fail("replay board has a 3BV of %d, %d stored", replay.ThreeBV, game.ThreeBV)
+		}
+		if replay.Undos != game.Undos {
+			fail("replay has %d undos, %d stored", replay.Undos, game.Undos)
+		}
+		if !sameBoard(replay.Board, game.Board) {
+			fail("replay board does not match the stored board")
+		}
+		if len(moves) > 0 && replay.TimeSpent != game.TimeSpent {
+			fail("replay takes %s, %s stored", replay.TimeSpent, game.TimeSpent)
+		}
+	}
+	result.Valid = len(result.Problems) == 0
+
+	if fastMoves >= maxFastMoves {
+		result.Suspicious = true
+		fail("%d moves came less than %s after the previous one", fastMoves, minHumanGap)
+	}
+
+	result.Passed = result.Valid && !result.Suspicious
+	return result
+}
+
+func sameBoard(a [][]byte, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if string(a[i]) != string(b[i]) {
+			return false
+		}
+	}
+	return true
+}
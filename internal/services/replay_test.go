@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayGameRebuildsBoardFromSeed(t *testing.T) {
+	game := &domain.Game{Rows: 9, Cols: 9, Mines: 10, FirstClick: "opening", Seed: 7, Status: "ready"}
+	generateBoard(game)
+
+	var moves []*domain.Move
+	var states [][][]byte
+	play := func(row int, col int, kind string) {
+		err := applyMove(game, &domain.ClickData{Row: row, Col: col, Kind: kind})
+		assert.Nil(t, err)
+		moves = append(moves, &domain.Move{Row: row, Col: col, Kind: kind, Status: game.Status, At: time.Now()})
+		states = append(states, copyBoard(game.Board))
+	}
+	play(4, 4, "click")
+	play(0, 0, "flag")
+	play(8, 8, "flag")
+
+	replay, err := replayGame(game, moves, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, states[1], replay.Board)
+
+	moves = append(moves, &domain.Move{Kind: "undo"})
+	replay, err = replayGame(game, moves, len(moves))
+	assert.Nil(t, err)
+	assert.Equal(t, states[1], replay.Board)
+	assert.Equal(t, 1, replay.Undos)
+
+	_, err = replayGame(game, moves, len(moves)+1)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyGame(t *testing.T) {
+	game := &domain.Game{Rows: 9, Cols: 9, Mines: 10, FirstClick: "opening", Seed: 7, Status: "ready"}
+	generateBoard(game)
+
+	var moves []*domain.Move
+	at := time.Now()
+	for _, cell := range [][2]int{{4, 4}, {0, 0}, {0, 8}, {8, 0}, {8, 8}} {
+		if game.Status == "ready" {
+			game.StartedAt = at
+		}
+		err := applyMove(game, &domain.ClickData{Row: cell[0], Col: cell[1], Kind: "flag"})
+		assert.Nil(t, err)
+		game.TimeSpent = at.Sub(game.StartedAt)
+		moves = append(moves, &domain.Move{Row: cell[0], Col: cell[1], Kind: "flag", Status: game.Status, At: at})
+		at = at.Add(time.Second)
+	}
+
+	result := verifyGame(game, moves)
+	assert.True(t, result.Passed, result.Problems)
+
+	game.Clicks = 1
+	result = verifyGame(game, moves)
+	assert.False(t, result.Valid)
+	game.Clicks = 0
+
+	bot := &domain.Game{Rows: 9, Cols: 9, Mines: 10, Seed: 7, Status: "ready"}
+	generateBoard(bot)
+	moves = nil
+	at = time.Now()
+	bot.StartedAt = at
+	for i := 0; i < 12; i++ {
+		err := applyMove(bot, &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+		assert.Nil(t, err)
+		bot.TimeSpent = at.Sub(bot.StartedAt)
+		moves = append(moves, &domain.Move{Row: 0, Col: 0, Kind: "flag", Status: bot.Status, At: at})
+		at = at.Add(time.Millisecond)
+	}
+
+	result = verifyGame(bot, moves)
+	assert.True(t, result.Valid, result.Problems)
+	assert.True(t, result.Suspicious)
+	assert.False(t, result.Passed)
+}
@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	apperrors "github.com/arllanos/minesweeper-API/internal/errors"
+	"github.com/arllanos/minesweeper-API/internal/repository"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(t *testing.T) (services.GameService, *repository.Repositories) {
+	repos := repository.NewMemoryRepositories()
+	service := services.NewGameService(repos.Games, repos.Leaderboards, testDailyKey)
+
+	_, err := service.CreateUser(&domain.User{Username: "alice"})
+	assert.Nil(t, err)
+	_, err = service.CreateUser(&domain.User{Username: "bob"})
+	assert.Nil(t, err)
+
+	return service, repos
+}
+
+func TestPlayGameEndToEnd(t *testing.T) {
+	service, _ := newTestService(t)
+
+	game, err := service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Difficulty: "beginner", Seed: 42})
+	assert.Nil(t, err)
+	assert.Equal(t, "ready", game.Status)
+	assert.Zero(t, game.Seed)
+
+	game, err = service.Click("game1", "alice", &domain.ClickData{Row: 4, Col: 4, Kind: "click"})
+	assert.Nil(t, err)
+	assert.Equal(t, "in_progress", game.Status)
+
+	_, err = service.Click("game1", "bob", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.EqualError(t, err, "forbidden")
+
+	_, err = service.CreateGame(&domain.Game{Name: "alice", Username: "alice"})
+	assert.Nil(t, err, "games and users do not share names")
+
+	moves, err := service.Moves("game1", "alice")
+	assert.Nil(t, err)
+	assert.Len(t, moves, 1)
+
+	page, err := service.Games("alice", []string{"in_progress"}, 0, "")
+	assert.Nil(t, err)
+	assert.Len(t, page.Games, 1)
+	assert.Equal(t, "game1", page.Games[0].Name)
+
+	assert.Nil(t, service.DeleteGame("game1", "alice"))
+	_, err = service.Board("game1", "alice")
+	assert.EqualError(t, err, "game_not_found")
+}
+
+func TestGamesArePaged(t *testing.T) {
+	service, _ := newTestService(t)
+
+	for _, name := range []string{"game1", "game2", "game3"} {
+		_, err := service.CreateGame(&domain.Game{Name: name, Username: "alice", Difficulty: "beginner", Seed: 42})
+		assert.Nil(t, err)
+	}
+	_, err := service.Click("game2", "alice", &domain.ClickData{Row: 4, Col: 4, Kind: "click"})
+	assert.Nil(t, err)
+
+	page, err := service.Games("alice", nil, 2, "")
+	assert.Nil(t, err)
+	assert.Len(t, page.Games, 2)
+	assert.Equal(t, "2", page.NextCursor)
+	for _, game := range page.Games {
+		assert.Nil(t, game.Board, "listings do not carry boards")
+	}
+
+	page, err = service.Games("alice", nil, 2, page.NextCursor)
+	assert.Nil(t, err)
+	assert.Len(t, page.Games, 1)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = service.Games("alice", []string{"ready"}, 1, "1")
+	assert.Nil(t, err)
+	assert.Len(t, page.Games, 1)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = service.Games("alice", []string{"lost"}, 0, "")
+	assert.EqualError(t, err, "bad_status")
+}
+
+func TestConcurrentClicksAreNotLost(t *testing.T) {
+	service, repos := newTestService(t)
+
+	_, err := service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Difficulty: "expert", Seed: 7})
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	applied := 0
+	for col := 0; col < 30; col++ {
+		wg.Add(1)
+		go func(col int) {
+			defer wg.Done()
+			_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: col, Kind: "flag"})
+			if err != nil {
+				assert.Equal(t, services.ErrGameConflict, err)
+				return
+			}
+			mu.Lock()
+			applied++
+			mu.Unlock()
+		}(col)
+	}
+	wg.Wait()
+
+	game, err := repos.Games.GetGame("game1")
+	assert.Nil(t, err)
+	flags := 0
+	for _, cell := range game.Board[0] {
+		if cell == 'e' || cell == 'm' {
+			flags++
+		}
+	}
+	assert.Equal(t, applied, flags)
+	assert.Equal(t, int64(applied+1), game.Version)
+
+	moves, err := repos.Games.GetMoves("game1")
+	assert.Nil(t, err)
+	assert.Len(t, moves, applied)
+
+	// moves are logged in the order they were saved, so the log replays
+	verification, err := service.Verify("game1", "alice")
+	assert.Nil(t, err)
+	assert.True(t, verification.Valid, "%v", verification.Problems)
+}
+
+func TestCreateGameFlagsCustomSeeds(t *testing.T) {
+	service, _ := newTestService(t)
+
+	game, err := service.CreateGame(&domain.Game{Username: "alice", Difficulty: "beginner", Seed: 42})
+	assert.Nil(t, err)
+	assert.True(t, game.CustomSeed)
+
+	// the flag comes from the seed, not from the request
+	game, err = service.CreateGame(&domain.Game{Username: "alice", Difficulty: "beginner", CustomSeed: true})
+	assert.Nil(t, err)
+	assert.False(t, game.CustomSeed)
+}
+
+func TestConcurrentResultsAreAllCounted(t *testing.T) {
+	service, repos := newTestService(t)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"game1", "game2"} {
+		_, err := service.CreateGame(&domain.Game{Name: name, Username: "alice", Difficulty: "beginner"})
+		assert.Nil(t, err)
+		row, col := findCell(t, repos, name, 'M')
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := service.Click(name, "alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+			assert.Nil(t, err)
+		}(name)
+	}
+	wg.Wait()
+
+	stats, err := service.Stats("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, stats.GamesPlayed)
+	assert.Equal(t, 2, stats.Losses)
+}
+
+func TestOnlyTheOwnerDeletesAGame(t *testing.T) {
+	service, repos := newTestService(t)
+	_, err := service.CreateUser(&domain.User{Username: "carol"})
+	assert.Nil(t, err)
+
+	_, err = service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Players: []string{"bob"}})
+	assert.Nil(t, err)
+
+	for _, userName := range []string{"bob", "carol"} {
+		err := service.DeleteGame("game1", userName)
+		assert.IsType(t, &apperrors.ForbiddenError{}, err, userName)
+	}
+	assert.True(t, repos.Games.GameExists("game1"))
+
+	_, err = service.Game("game1", "carol")
+	assert.IsType(t, &apperrors.ForbiddenError{}, err)
+	game, err := service.Game("game1", "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", game.Username)
+
+	assert.EqualError(t, service.DeleteGame("game1", "nobody"), "user_not_found")
+	assert.Nil(t, service.DeleteGame("game1", "alice"))
+	assert.False(t, repos.Games.GameExists("game1"))
+}
+
+func TestNamesCannotReachOtherKeys(t *testing.T) {
+	service, _ := newTestService(t)
+
+	// user:alice:games is the index of the games of alice in Redis
+	for _, name := range []string{"alice:games", "", "a b", "alice/1"} {
+		_, err := service.CreateUser(&domain.User{Username: name})
+		assert.EqualError(t, err, "bad_username", name)
+	}
+
+	// game:g:board is the board of game g in Redis
+	for _, name := range []string{"g:board", "g:undo", "g:moves", "a b"} {
+		_, err := service.CreateGame(&domain.Game{Name: name, Username: "alice", Difficulty: "beginner"})
+		assert.EqualError(t, err, "bad_game_name", name)
+	}
+
+	_, err := service.CreateGame(&domain.Game{Name: "alice.game_1-b", Username: "alice", Difficulty: "beginner"})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"math/rand"
+	"unicode"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+// maxNoGuessAttempts bounds how many layouts are tried when looking for a
+// board that can be solved without guessing.
+const maxNoGuessAttempts = 2000
+
+const (
+	cellUnknown = iota
+	cellSafe
+	cellMine
+)
+
+// constraint states that exactly Mines of the given veiled cells are mines.
+// It originates in the revealed number at (row, col), or in the total mine
+// count of the board when row and col are -1.
+type constraint struct {
+	row   int
+	col   int
+	cells [][2]int
+	mines int
+}
+
+// deduction is a cell proven to be safe or a mine together with the
+// constraints that prove it.
+type deduction struct {
+	row         int
+	col         int
+	mine        bool
+	rule        string
+	constraints []constraint
+}
+
+// solver deduces safe cells and mines from what a player can see on a board.
+// It only reads revealed cells (digits and 'B'), every other value is treated
+// as veiled so it can run on raw boards as well as on masked ones. Flags are
+// not trusted since players can misplace them.
+type solver struct {
+	board [][]byte
+	rows  int
+	cols  int
+	mines int
+	known [][]int
+}
+
+func newSolver(board [][]byte, mines int) *solver {
+	s := &solver{board: board, rows: len(board), mines: mines}
+	if s.rows > 0 {
+		s.cols = len(board[0])
+	}
+	s.known = make([][]int, s.rows)
+	for i := range s.known {
+		s.known[i] = make([]int, s.cols)
+	}
+	return s
+}
+
+func isRevealed(value byte) bool {
+	return value == 'B' || (value >= '1' && value <= '8')
+}
+
+// undetermined reports whether the cell is veiled and not yet deduced.
+func (s *solver) undetermined(r int, c int) bool {
+	return !isRevealed(s.board[r][c]) && s.known[r][c] == cellUnknown
+}
+
+// constraints builds one constraint per revealed number that still has
+// undetermined neighbours, discounting the mines already deduced.
+func (s *solver) constraints() []constraint {
+	var result []constraint
+	for r := 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c++ {
+			value := s.board[r][c]
+			if value < '1' || value > '8' {
+				continue
+			}
+			cons := constraint{row: r, col: c, mines: int(value - '0')}
+			for _, d := range dirVector {
+				x, y := r+d[0], c+d[1]
+				if !(x >= 0 && x < s.rows && y >= 0 && y < s.cols) {
+					continue
+				}
+				if s.known[x][y] == cellMine {
+					cons.mines--
+				} else if s.undetermined(x, y) {
+					cons.cells = append(cons.cells, [2]int{x, y})
+				}
+			}
+			if len(cons.cells) > 0 {
+				result = append(result, cons)
+			}
+		}
+	}
+	return result
+}
+
+// globalConstraint relates the mines left to every undetermined cell.
+func (s *solver) globalConstraint() constraint {
+	cons := constraint{row: -1, col: -1, mines: s.mines}
+	for r := 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c++ {
+			if s.known[r][c] == cellMine {
+				cons.mines--
+			} else if s.undetermined(r, c) {
+				cons.cells = append(cons.cells, [2]int{r, c})
+			}
+		}
+	}
+	return cons
+}
+
+// deduce returns every cell that can be proven safe or a mine with the
+// simplest rule that yields something: a single constraint, a pair of
+// overlapping constraints, or the total mine count.
+func (s *solver) deduce() []deduction {
+	constraints := s.constraints()
+
+	var found []deduction
+	seen := map[[2]int]bool{}
+	add := func(cells [][2]int, mine bool, rule string, proof ...constraint) {
+		for _, cell := range cells {
+			if seen[cell] {
+				continue
+			}
+			seen[cell] = true
+			found = append(found, deduction{row: cell[0], col: cell[1], mine: mine, rule: rule, constraints: proof})
+		}
+	}
+
+	// single constraint: all mines or all safe
+	for _, cons := range constraints {
+		if cons.mines == 0 {
+			add(cons.cells, false, "single", cons)
+		} else if cons.mines == len(cons.cells) {
+			add(cons.cells, true, "single", cons)
+		}
+	}
+	if len(found) > 0 {
+		return found
+	}
+
+	// pairs of constraints sharing cells
+	byCell := map[[2]int][]int{}
+	for i, cons := range constraints {
+		for _, cell := range cons.cells {
+			byCell[cell] = append(byCell[cell], i)
+		}
+	}
+	for i, a := range constraints {
+		peers := map[int]bool{}
+		for _, cell := range a.cells {
+			for _, j := range byCell[cell] {
+				if j != i {
+					peers[j] = true
+				}
+			}
+		}
+		for j := range peers {
+			b := constraints[j]
+			onlyA, onlyB := difference(a.cells, b.cells), difference(b.cells, a.cells)
+			if len(onlyB) > 0 && b.mines-a.mines == len(onlyB) {
+				// every mine b has outside a is needed: they are mines and the rest of a is safe
+				add(onlyB, true, "pair", a, b)
+				add(onlyA, false, "pair", a, b)
+			} else if len(onlyA) == 0 && len(onlyB) > 0 && b.mines == a.mines {
+				// a is contained in b and already holds all of its mines
+				add(onlyB, false, "pair", a, b)
+			}
+		}
+	}
+	if len(found) > 0 {
+		return found
+	}
+
+	// total mine count
+	global := s.globalConstraint()
+	if global.mines == 0 {
+		add(global.cells, false, "global", global)
+	} else if global.mines == len(global.cells) {
+		add(global.cells, true, "global", global)
+	}
+	return found
+}
+
+// difference returns the cells of a that are not in b.
+func difference(a [][2]int, b [][2]int) [][2]int {
+	var result [][2]int
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// solvable plays a copy of the game from a first click at (r, c) using only
+// logic deductions and reports whether it can be won without guessing.
+func solvable(game *domain.Game, r int, c int) bool {
+	sim := *game
+	sim.Board = copyBoard(game.Board)
+	sim.Status = "in_progress"
+	if err := clickCell(&sim, r, c); err != nil || sim.Status == "over" {
+		return false
+	}
+
+	s := newSolver(sim.Board, sim.Mines)
+	for !weHaveWinner(&sim) {
+		deductions := s.deduce()
+		if len(deductions) == 0 {
+			return false
+		}
+		for _, d := range deductions {
+			if d.mine {
+				s.known[d.row][d.col] = cellMine
+				continue
+			}
+			if isRevealed(sim.Board[d.row][d.col]) {
+				continue
+			}
+			// flags do not matter for the simulation, reveal through them
+			sim.Board[d.row][d.col] = byte(unicode.ToUpper(rune(sim.Board[d.row][d.col])))
+			if err := clickCell(&sim, d.row, d.col); err != nil || sim.Status == "over" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// plantSolvableMines plants mines like plantMines does but only keeps a layout
+// that can be won from the first click at (r, c) without guessing. It reports
+// whether such a layout was found, leaving the board untouched otherwise.
+func plantSolvableMines(game *domain.Game, r int, c int) bool {
+	// attempts follow each other in the seeded sequence so the result is reproducible
+	rng := rand.New(rand.NewSource(game.Seed))
+	initial := copyBoard(game.Board)
+	for attempt := 0; attempt < maxNoGuessAttempts; attempt++ {
+		game.Board = copyBoard(initial)
+		plantMinesWith(rng, game, r, c)
+		if solvable(game, r, c) {
+			return true
+		}
+	}
+	game.Board = initial
+	return false
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolverDeducesOneTwoOnePattern(t *testing.T) {
+	board := [][]byte{
+		{'M', 'E', 'M'},
+		{'1', '2', '1'},
+		{'B', 'B', 'B'},
+	}
+	s := newSolver(board, 2)
+
+	mines := map[[2]int]bool{}
+	for _, d := range s.deduce() {
+		assert.True(t, d.mine)
+		assert.Equal(t, "pair", d.rule)
+		mines[[2]int{d.row, d.col}] = true
+		s.known[d.row][d.col] = cellMine
+	}
+	assert.Equal(t, map[[2]int]bool{{0, 0}: true, {0, 2}: true}, mines)
+
+	deductions := s.deduce()
+	assert.Len(t, deductions, 1)
+	assert.False(t, deductions[0].mine)
+	assert.Equal(t, [2]int{0, 1}, [2]int{deductions[0].row, deductions[0].col})
+}
+
+func TestFindHintBuildsOnFlaggedMines(t *testing.T) {
+	var game domain.Game
+	game.Rows = 3
+	game.Cols = 3
+	game.Mines = 2
+	game.Status = "in_progress"
+	game.Board = [][]byte{
+		{'m', 'E', 'M'},
+		{'1', '2', '1'},
+		{'B', 'B', 'B'},
+	}
+
+	hint := findHint(&game)
+
+	assert.True(t, hint.Mine)
+	assert.Equal(t, [2]int{0, 2}, [2]int{hint.Row, hint.Col})
+	assert.Len(t, hint.Constraints, 2)
+
+	game.Board[0][2] = 'm'
+	hint = findHint(&game)
+
+	assert.False(t, hint.Mine)
+	assert.Equal(t, [2]int{0, 1}, [2]int{hint.Row, hint.Col})
+	assert.Equal(t, "single", hint.Rule)
+}
+
+func TestSolverProbabilities(t *testing.T) {
+	board := [][]byte{
+		{'1', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'E'},
+		{'E', 'E', 'E', 'E'},
+	}
+	s := newSolver(board, 3)
+
+	probs, exact := s.probabilities()
+
+	assert.True(t, exact)
+	assert.Equal(t, float64(0), probs[0][0])
+	assert.InDelta(t, 1.0/3, probs[0][1], 1e-9)
+	assert.InDelta(t, 1.0/3, probs[1][1], 1e-9)
+	assert.InDelta(t, 1.0/6, probs[3][3], 1e-9)
+}
+
+func TestHeatmapBeforeMinesArePlanted(t *testing.T) {
+	game := &domain.Game{Rows: 9, Cols: 9, Mines: 10, FirstClick: "safe", Status: "ready", Seed: 1}
+	generateBoard(game)
+
+	heatmap := mineHeatmap(game)
+
+	assert.False(t, heatmap.Exact)
+	total := 0.0
+	for _, row := range heatmap.Probabilities {
+		for _, p := range row {
+			assert.InDelta(t, 10.0/81, p, 1e-9)
+			total += p
+		}
+	}
+	assert.InDelta(t, 10.0, total, 1e-9)
+}
+
+func TestHintsAfterAFlagBeforeMinesArePlanted(t *testing.T) {
+	game := &domain.Game{Rows: 9, Cols: 9, Mines: 10, FirstClick: "safe", Status: "ready", Seed: 1}
+	generateBoard(game)
+	assert.Nil(t, applyMove(game, &domain.ClickData{Row: 4, Col: 4, Kind: "flag"}))
+	assert.False(t, minesPlanted(game))
+
+	hint := findHint(game)
+	if assert.NotNil(t, hint) {
+		assert.Equal(t, "first_click", hint.Rule)
+		assert.NotEqual(t, [2]int{4, 4}, [2]int{hint.Row, hint.Col}, "the flagged cell cannot be clicked")
+	}
+
+	heatmap := mineHeatmap(game)
+	assert.False(t, heatmap.Exact)
+	assert.InDelta(t, 10.0/81, heatmap.Probabilities[0][0], 1e-9)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+)
+
+// recordOutcome adds the result of a finished game to the stats of a user.
+// Only the first result of a game counts: undoing a losing click and winning
+// afterwards does not turn the loss into a win.
+func recordOutcome(stats *domain.UserStats, game *domain.Game) {
+	if game.Status != "won" && game.Status != "over" {
+		return
+	}
+
+	stats.GamesPlayed++
+	if game.Status == "over" {
+		stats.Losses++
+		stats.CurrentStreak = 0
+	} else {
+		stats.Wins++
+		stats.CurrentStreak++
+		if stats.CurrentStreak > stats.LongestStreak {
+			stats.LongestStreak = stats.CurrentStreak
+		}
+		stats.AverageEfficiency += (game.Efficiency - stats.AverageEfficiency) / float64(stats.Wins)
+
+		// best times are only kept for unassisted solo games on preset boards
+		// the server picked the seed of
+		if _, ok := presets[game.Difficulty]; ok && game.Hints == 0 && game.Undos == 0 && len(game.Players) == 0 && !game.CustomSeed {
+			if stats.BestTimes == nil {
+				stats.BestTimes = map[string]time.Duration{}
+			}
+			if best, ok := stats.BestTimes[game.Difficulty]; !ok || game.TimeSpent < best {
+				stats.BestTimes[game.Difficulty] = game.TimeSpent
+			}
+		}
+	}
+	stats.WinRate = float64(stats.Wins) / float64(stats.GamesPlayed)
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordOutcomeTracksStreaksAndBestTimes(t *testing.T) {
+	var stats domain.UserStats
+	result := func(status string, seconds int) *domain.Game {
+		return &domain.Game{Difficulty: "beginner", Status: status, TimeSpent: time.Duration(seconds) * time.Second, Efficiency: 1}
+	}
+
+	recordOutcome(&stats, result("won", 40))
+	recordOutcome(&stats, result("won", 30))
+	recordOutcome(&stats, result("over", 5))
+	recordOutcome(&stats, result("won", 50))
+	recordOutcome(&stats, result("in_progress", 1))
+
+	assert.Equal(t, 4, stats.GamesPlayed)
+	assert.Equal(t, 3, stats.Wins)
+	assert.Equal(t, 1, stats.Losses)
+	assert.Equal(t, 0.75, stats.WinRate)
+	assert.Equal(t, 1, stats.CurrentStreak)
+	assert.Equal(t, 2, stats.LongestStreak)
+	assert.Equal(t, 30*time.Second, stats.BestTimes["beginner"])
+	assert.Equal(t, float64(1), stats.AverageEfficiency)
+}
+
+func TestRecordOutcomeSkipsBestTimesOfCustomSeeds(t *testing.T) {
+	var stats domain.UserStats
+
+	recordOutcome(&stats, &domain.Game{Difficulty: "beginner", Status: "won", TimeSpent: time.Second, CustomSeed: true})
+
+	assert.Equal(t, 1, stats.Wins)
+	assert.Empty(t, stats.BestTimes)
+}
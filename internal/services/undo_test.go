@@ -0,0 +1,281 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arllanos/minesweeper-API/internal/domain"
+	"github.com/arllanos/minesweeper-API/internal/repository"
+	"github.com/arllanos/minesweeper-API/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUndoGame creates game1 of alice on a classic board, mines are laid out
+// from the start so the first click can hit one.
+func newUndoGame(t *testing.T, undoLimit int) (services.GameService, *repository.Repositories) {
+	service, repos := newTestService(t)
+	_, err := service.CreateGame(&domain.Game{Name: "game1", Username: "alice", Difficulty: "beginner", Seed: 42, UndoLimit: undoLimit})
+	assert.Nil(t, err)
+	return service, repos
+}
+
+// clickOn clicks the first cell of game1 holding value on the stored board.
+func clickOn(t *testing.T, service services.GameService, repos *repository.Repositories, value byte) (*domain.Game, error) {
+	row, col := findCell(t, repos, "game1", value)
+	return service.Click("game1", "alice", &domain.ClickData{Row: row, Col: col, Kind: "click"})
+}
+
+func showsMines(board [][]byte) bool {
+	for _, row := range board {
+		for _, cell := range row {
+			if cell == 'M' || cell == 'm' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestLostGameStaysMaskedWhileUndoable(t *testing.T) {
+	service, repos := newUndoGame(t, 1)
+
+	game, err := clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+	assert.False(t, showsMines(game.Board))
+	assert.Zero(t, game.Seed)
+
+	data, err := service.Board("game1", "alice")
+	assert.Nil(t, err)
+	var board [][]string
+	assert.Nil(t, json.Unmarshal(data, &board))
+	for _, row := range board {
+		assert.NotContains(t, row, "M")
+	}
+
+	_, err = service.Replay("game1", "alice", -1)
+	assert.EqualError(t, err, "game_in_progress")
+
+	game, err = service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "ready", game.Status)
+
+	// with no undo left the loss is final and the layout disclosed
+	game, err = clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+	assert.True(t, showsMines(game.Board))
+	assert.Equal(t, int64(42), game.Seed)
+}
+
+func TestUndoHistoryIsCapped(t *testing.T) {
+	service, repos := newUndoGame(t, 2)
+
+	for col := 0; col < 6; col++ {
+		_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: col, Kind: "flag"})
+		assert.Nil(t, err)
+	}
+
+	kept := 0
+	for ; kept < 6; kept++ {
+		snapshot, err := repos.Games.LastSnapshot("game1")
+		assert.Nil(t, err)
+		if snapshot == nil {
+			break
+		}
+		game, err := repos.Games.GetGame("game1")
+		assert.Nil(t, err)
+		_, err = repos.Games.SaveGameHistory(game, &services.GameHistory{Pop: true})
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, kept)
+}
+
+func TestUndoLosingClick(t *testing.T) {
+	service, repos := newUndoGame(t, 3)
+
+	_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.Nil(t, err)
+	before, err := repos.Games.GetGame("game1")
+	assert.Nil(t, err)
+
+	game, err := clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+
+	game, err = service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "in_progress", game.Status)
+	assert.Equal(t, before.Clicks, game.Clicks)
+	assert.Equal(t, 1, game.Undos)
+
+	stored, err := repos.Games.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, before.Board, stored.Board)
+
+	moves, err := service.Moves("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "undo", moves[len(moves)-1].Kind)
+}
+
+func TestUndoBudget(t *testing.T) {
+	service, _ := newUndoGame(t, 1)
+
+	for col := 0; col < 2; col++ {
+		_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: col, Kind: "flag"})
+		assert.Nil(t, err)
+	}
+
+	_, err := service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	_, err = service.Undo("game1", "alice")
+	assert.EqualError(t, err, "undo_budget_exhausted")
+}
+
+func TestUndoDisabled(t *testing.T) {
+	service, _ := newUndoGame(t, 0)
+
+	_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.Nil(t, err)
+
+	_, err = service.Undo("game1", "alice")
+	assert.EqualError(t, err, "undo_disabled")
+}
+
+func TestUndoWithNothingToUndo(t *testing.T) {
+	service, _ := newUndoGame(t, 2)
+
+	_, err := service.Undo("game1", "alice")
+	assert.EqualError(t, err, "nothing_to_undo")
+
+	_, err = service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.Nil(t, err)
+	_, err = service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	_, err = service.Undo("game1", "alice")
+	assert.EqualError(t, err, "nothing_to_undo")
+}
+
+// conflictingRepo fails the next conflicts saves of games as if someone else
+// saved them first.
+type conflictingRepo struct {
+	services.GameRepository
+	conflicts int
+}
+
+func (r *conflictingRepo) SaveGameHistory(game *domain.Game, history *services.GameHistory) (*domain.Game, error) {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return nil, services.ErrGameConflict
+	}
+	return r.GameRepository.SaveGameHistory(game, history)
+}
+
+func TestUndoConflictKeepsSnapshot(t *testing.T) {
+	_, repos := newUndoGame(t, 2)
+	repo := &conflictingRepo{GameRepository: repos.Games}
+	service := services.NewGameService(repo, repos.Leaderboards, testDailyKey)
+
+	_, err := service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.Nil(t, err)
+
+	// a conflict is retried on the new state of the game...
+	repo.conflicts = 1
+	game, err := service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, game.Undos)
+
+	_, err = service.Click("game1", "alice", &domain.ClickData{Row: 0, Col: 0, Kind: "flag"})
+	assert.Nil(t, err)
+
+	// ...and when every attempt conflicts, neither the game nor its history
+	// is changed
+	repo.conflicts = 3
+	_, err = service.Undo("game1", "alice")
+	assert.Equal(t, services.ErrGameConflict, err)
+
+	stored, err := repos.Games.GetGame("game1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stored.Undos)
+	snapshot, err := repos.Games.LastSnapshot("game1")
+	assert.Nil(t, err)
+	assert.NotNil(t, snapshot)
+
+	// the player can try again
+	game, err = service.Undo("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, game.Undos)
+	assert.Equal(t, "ready", game.Status)
+}
+
+func TestResignDisclosesLostGame(t *testing.T) {
+	service, repos := newUndoGame(t, 3)
+
+	_, err := service.Resign("game1", "alice")
+	assert.EqualError(t, err, "game_not_lost")
+
+	_, err = clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+
+	game, err := service.Resign("game1", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "over", game.Status)
+	assert.Equal(t, 3, game.Undos)
+	assert.True(t, showsMines(game.Board))
+	assert.Equal(t, int64(42), game.Seed)
+
+	replay, err := service.Replay("game1", "alice", -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "over", replay.Status)
+
+	verification, err := service.Verify("game1", "alice")
+	assert.Nil(t, err)
+	assert.True(t, verification.Valid, "%v", verification.Problems)
+
+	_, err = service.Resign("game1", "alice")
+	assert.EqualError(t, err, "loss_already_final")
+	_, err = service.Undo("game1", "alice")
+	assert.EqualError(t, err, "undo_budget_exhausted")
+}
+
+func TestUndoneLossIsNotCounted(t *testing.T) {
+	service, repos := newUndoGame(t, 1)
+
+	_, err := clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+	_, err = service.Undo("game1", "alice")
+	assert.Nil(t, err)
+
+	stats, err := service.Stats("alice")
+	assert.Nil(t, err)
+	assert.Zero(t, stats.GamesPlayed, "a loss that can be undone is not final")
+
+	game := &domain.Game{Status: "ready"}
+	for game.Status != "won" {
+		if game, err = clickOn(t, service, repos, 'E'); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	stats, err = service.Stats("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stats.GamesPlayed)
+	assert.Equal(t, 1, stats.Wins)
+	assert.Zero(t, stats.Losses)
+}
+
+func TestResignCountsTheLoss(t *testing.T) {
+	service, repos := newUndoGame(t, 2)
+
+	_, err := clickOn(t, service, repos, 'M')
+	assert.Nil(t, err)
+	stats, err := service.Stats("alice")
+	assert.Nil(t, err)
+	assert.Zero(t, stats.Losses)
+
+	_, err = service.Resign("game1", "alice")
+	assert.Nil(t, err)
+	stats, err = service.Stats("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stats.Losses)
+}